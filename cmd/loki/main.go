@@ -19,20 +19,46 @@ import (
 	"flag"
 	"io/ioutil"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/narahari92/loki/pkg/audit"
+	"github.com/narahari92/loki/pkg/command"
+	"github.com/narahari92/loki/pkg/httpprobe"
 	"github.com/narahari92/loki/pkg/loki"
 	"github.com/narahari92/loki/pkg/rego"
 	"github.com/narahari92/loki/pkg/system/kubernetes"
 )
 
+const (
+	planCommand = "plan"
+	textFormat  = "text"
+	jsonFormat  = "json"
+	junitFormat = "junit"
+)
+
 func main() {
 	logger := logrus.New()
 
-	if err := run(context.Background(), logger); err != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		logger.Warnf("received signal '%s', aborting chaos execution. Send it again to exit immediately", sig)
+		cancel()
+
+		// second signal gets the default behaviour of terminating the process, in case abort doesn't complete.
+		signal.Stop(signals)
+	}()
+
+	if err := run(ctx, logger); err != nil {
 		logger.Error(err)
 		os.Exit(1)
 	}
@@ -43,8 +69,34 @@ func run(ctx context.Context, logger logrus.FieldLogger) error {
 
 	configFile := flag.String("config", "", "configuration yaml for execution")
 	reportLocation := flag.String("report", "", "location where the report file will be created")
+	reportFormat := flag.String("report-format", jsonFormat, "format of the report file, one of 'json' or 'junit'")
+	runPolicy := flag.String("run-policy", "", "policy on scenario failure, one of 'failFast', 'runAll' or 'stopAfterFailures'. "+
+		"Overrides the policy of configuration")
+	maxFailures := flag.Int("max-failures", 0, "number of failed scenarios after which execution stops for 'stopAfterFailures' run policy")
+	seed := flag.Int64("seed", 0, "seed used to generate random scenarios of all systems. Overrides the seeds of configuration "+
+		"and can be used to reproduce the scenarios of a previous report")
+	concurrency := flag.Int("concurrency", 0, "number of systems whose chaos scenarios are executed concurrently. "+
+		"Overrides the configuration")
+	failOnDegraded := flag.Bool("fail-on-degraded", false, "fail chaos execution if a scenario recovers later than its "+
+		"recovery objective. Overrides the configuration")
+	dryRun := flag.Bool("dry-run", false, "prints the scenarios which would be executed without killing anything. "+
+		"Same as 'plan' command")
+	planFormat := flag.String("plan-format", textFormat, "format in which plan is printed, one of 'text' or 'json'")
+	checkpointInterval := flag.Duration("checkpoint-interval", 0, "interval at which report of scenarios executed so far "+
+		"is written while chaos is executed. Overrides the configuration")
+
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == planCommand {
+		*dryRun = true
+		args = args[1:]
+	}
+
+	// flag.CommandLine exits on failure to parse flags.
+	_ = flag.CommandLine.Parse(args)
 
-	flag.Parse()
+	if *reportFormat != jsonFormat && *reportFormat != junitFormat {
+		return errors.Errorf("unsupported report format '%s'", *reportFormat)
+	}
 
 	configuration, err := ioutil.ReadFile(*configFile)
 	if err != nil {
@@ -57,38 +109,123 @@ func run(ctx context.Context, logger logrus.FieldLogger) error {
 		return errors.Wrap(err, "failed to parse configuration")
 	}
 
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			config.SetSeed(*seed)
+		case "fail-on-degraded":
+			config.SetFailOnDegraded(*failOnDegraded)
+		}
+	})
+
+	if *runPolicy != "" || *maxFailures > 0 {
+		policy := loki.StopAfterFailures
+		if *runPolicy != "" {
+			if policy, err = loki.ParseRunPolicy(*runPolicy); err != nil {
+				return err
+			}
+		}
+
+		if err := config.SetRunPolicy(policy, *maxFailures); err != nil {
+			return errors.Wrap(err, "failed to set run policy")
+		}
+	}
+
+	if *concurrency != 0 {
+		if err := config.SetConcurrency(*concurrency); err != nil {
+			return errors.Wrap(err, "failed to set concurrency")
+		}
+	}
+
+	if *checkpointInterval > 0 {
+		if err := config.SetCheckpointInterval(*checkpointInterval); err != nil {
+			return errors.Wrap(err, "failed to set checkpoint interval")
+		}
+	}
+
 	chaosMaker := loki.ChaosMaker{
 		Config:      config,
 		FieldLogger: logger,
 		Reporter:    &audit.Reporter{},
 	}
 
-	defer func() {
-		if reportLocation == nil || *reportLocation == "" {
-			return
-		}
+	if *dryRun {
+		return printPlan(ctx, &chaosMaker, *planFormat)
+	}
 
-		file, err := os.Create(*reportLocation)
-		if err != nil {
-			logger.WithError(err).Errorf("failed to create report file %s", *reportLocation)
-		}
+	if *reportLocation == "" {
+		return createChaos(ctx, &chaosMaker)
+	}
 
-		if err = chaosMaker.Reporter.Report(file); err != nil {
-			logger.WithError(err).Errorf("failed to write report into file %s", *reportLocation)
-		}
+	checkpoint := func(context.Context) error {
+		return writeReport(chaosMaker.Reporter, *reportLocation, *reportFormat)
+	}
 
-		if err = file.Close(); err != nil {
-			logger.WithError(err).Errorf("failed to close report file %s", *reportLocation)
+	defer func() {
+		if err := checkpoint(ctx); err != nil {
+			logger.Error(err)
 		}
 	}()
 
-	if err := chaosMaker.CreateChaos(ctx); err != nil {
+	return createChaos(ctx, &chaosMaker, loki.WithCheckpoint(checkpoint))
+}
+
+func createChaos(ctx context.Context, chaosMaker *loki.ChaosMaker, opts ...loki.HookOption) error {
+	if err := chaosMaker.CreateChaos(ctx, opts...); err != nil {
 		return errors.Wrap(err, "failure in chaos")
 	}
 
 	return nil
 }
 
+// writeReport writes the report into a temporary file which then replaces the one at location, so that report written
+// at a previous checkpoint stays intact if writing fails midway.
+func writeReport(reporter *audit.Reporter, location, format string) error {
+	temporary := location + ".tmp"
+
+	file, err := os.Create(temporary)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create report file %s", temporary)
+	}
+
+	report := reporter.Report
+	if format == junitFormat {
+		report = reporter.ReportJUnit
+	}
+
+	if err = report(file); err != nil {
+		_ = file.Close()
+		return errors.Wrapf(err, "failed to write report into file %s", temporary)
+	}
+
+	if err = file.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close report file %s", temporary)
+	}
+
+	if err = os.Rename(temporary, location); err != nil {
+		return errors.Wrapf(err, "failed to move report file %s to %s", temporary, location)
+	}
+
+	return nil
+}
+
+func printPlan(ctx context.Context, chaosMaker *loki.ChaosMaker, format string) error {
+	if format != textFormat && format != jsonFormat {
+		return errors.Errorf("unsupported plan format '%s'", format)
+	}
+
+	plan, err := chaosMaker.Plan(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to plan chaos")
+	}
+
+	if format == jsonFormat {
+		return plan.WriteJSON(os.Stdout)
+	}
+
+	return plan.WriteText(os.Stdout)
+}
+
 func registerDependencies() {
 	const afterKey = "after"
 
@@ -96,5 +233,9 @@ func registerDependencies() {
 
 	loki.RegisterReadyParser(afterKey, loki.AfterParser)
 	rego.RegisterReadyParser()
+	httpprobe.RegisterReadyParser()
+	httpprobe.RegisterHookParser()
+	command.RegisterReadyParser()
+	command.RegisterHookParser()
 
 }
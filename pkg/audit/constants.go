@@ -5,4 +5,8 @@ const (
 	SuccessResult = "Success"
 	// FailureResult indicates failure of result in message.
 	FailureResult = "Failure"
+	// DegradedResult indicates that system recovered but took longer than expected.
+	DegradedResult = "Degraded"
+	// AbortedResult indicates that execution was interrupted before result could be determined.
+	AbortedResult = "Aborted"
 )
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// ReportJUnit writes the JUnit XML representation of Reporter into the writer passed. Ready phase, load phase, hooks
+// and chaos test scenarios are represented as test cases. Failed ones are reported as failures and aborted ones as
+// errors. Degraded scenarios recovered, so they pass with the reason of degradation as output. Captured output of
+// steps is written as output of their test cases. Hooks which weren't executed are left out.
+func (r *Reporter) ReportJUnit(writer io.Writer) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	ready := &junitTestSuite{Name: "ready"}
+	ready.add("ready", "pre ready hook", r.Ready.PreReady)
+	ready.add("ready", "ready", r.Ready.Message)
+	ready.add("ready", "post ready hook", r.Ready.PostReady)
+
+	load := &junitTestSuite{Name: "load"}
+	load.add("load", "pre system load hook", r.Load.PreLoad)
+	load.add("load", "load", r.Load.Message)
+	load.add("load", "post system load hook", r.Load.PostLoad)
+
+	scenarios := &junitTestSuite{Name: "scenarios"}
+	scenarios.add("scenarios", "pre chaos test hook", r.Scenarios.PreChaosTests)
+
+	for i, scenario := range r.Scenarios.Scenarios {
+		className := "scenarios"
+		if scenario.System != "" {
+			className = fmt.Sprintf("scenarios.%s", scenario.System)
+		}
+
+		scenarios.add(className, fmt.Sprintf("scenario %d: %s", i+1, scenario.Identifiers), scenario.Message)
+	}
+
+	scenarios.add("scenarios", "post chaos test hook", r.Scenarios.PostChaosTests)
+
+	suites := &junitTestSuites{Name: "loki"}
+
+	var total time.Duration
+
+	for _, suite := range []*junitTestSuite{ready, load, scenarios} {
+		suite.Time = seconds(suite.duration)
+		total += suite.duration
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, *suite)
+	}
+
+	suites.Time = seconds(total)
+
+	report, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall junit report")
+	}
+
+	if _, err := writer.Write(append([]byte(xml.Header), report...)); err != nil {
+		return errors.Wrap(err, "failed to write junit report")
+	}
+
+	return nil
+}
+
+// add adds the message as test case of suite unless the message is empty, which happens when reported step wasn't
+// executed.
+func (s *junitTestSuite) add(className, name string, message Message) {
+	if message.Result == "" {
+		return
+	}
+
+	testCase := junitTestCase{
+		Name:      name,
+		ClassName: className,
+		Time:      seconds(message.Duration),
+	}
+
+	switch message.Result {
+	case FailureResult:
+		testCase.Failure = &junitProblem{Message: message.Message, Type: FailureResult, Content: message.Message}
+		s.Failures++
+	case AbortedResult:
+		testCase.Error = &junitProblem{Message: message.Message, Type: AbortedResult, Content: message.Message}
+		s.Errors++
+	default:
+		testCase.SystemOut = message.Message
+	}
+
+	if message.Output != "" {
+		testCase.SystemOut = strings.TrimPrefix(testCase.SystemOut+"\n"+message.Output, "\n")
+	}
+
+	s.Tests++
+	s.duration += message.Duration
+	s.TestCases = append(s.TestCases, testCase)
+}
+
+func seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportJUnit(t *testing.T) {
+	expectedReport := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="loki" tests="5" failures="1" errors="1" time="4.500">
+  <testsuite name="ready" tests="1" failures="0" errors="0" time="1.000">
+    <testcase name="ready" classname="ready" time="1.000">
+      <system-out>Successfully completed ready phase</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="load" tests="1" failures="0" errors="0" time="0.500">
+    <testcase name="load" classname="load" time="0.500">
+      <system-out>system(s) are loaded successfully</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="scenarios" tests="3" failures="1" errors="1" time="3.000">
+    <testcase name="scenario 1: id1" classname="scenarios.system1" time="1.000">
+      <system-out>Successfully executed the scenario</system-out>
+    </testcase>
+    <testcase name="scenario 2: id2" classname="scenarios.system1" time="2.000">
+      <failure message="system didn&#39;t reach desired state" type="Failure">system didn&#39;t reach desired state</failure>
+      <system-out>pod crashed</system-out>
+    </testcase>
+    <testcase name="scenario 3: id3" classname="scenarios.system2" time="0.000">
+      <error message="scenario aborted" type="Aborted">scenario aborted</error>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	reporter := &Reporter{
+		Ready: Ready{
+			Message: Message{
+				Result:   SuccessResult,
+				Message:  "Successfully completed ready phase",
+				Duration: time.Second,
+			},
+		},
+		Load: Load{
+			Message: Message{
+				Result:   SuccessResult,
+				Message:  "system(s) are loaded successfully",
+				Duration: 500 * time.Millisecond,
+			},
+		},
+		Scenarios: Scenarios{
+			Scenarios: []Scenario{
+				{
+					System:      "system1",
+					Identifiers: "id1",
+					Message: Message{
+						Result:   SuccessResult,
+						Message:  "Successfully executed the scenario",
+						Duration: time.Second,
+					},
+				},
+				{
+					System:      "system1",
+					Identifiers: "id2",
+					Message: Message{
+						Result:   FailureResult,
+						Message:  "system didn't reach desired state",
+						Duration: 2 * time.Second,
+						Output:   "pod crashed",
+					},
+				},
+				{
+					System:      "system2",
+					Identifiers: "id3",
+					Message: Message{
+						Result:  AbortedResult,
+						Message: "scenario aborted",
+					},
+				},
+			},
+		},
+	}
+	writer := &bytes.Buffer{}
+
+	err := reporter.ReportJUnit(writer)
+	require.NoError(t, err)
+	require.Equal(t, expectedReport, writer.String())
+}
@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"sort"
+	"time"
+)
+
+// Recovery contains the aggregates of recovery time of the scenarios of a system which recovered.
+type Recovery struct {
+	// Scenarios is the number of scenarios from which system recovered.
+	Scenarios int `json:"scenarios"`
+	// P50 is the median recovery time.
+	P50 time.Duration `json:"p50"`
+	// P95 is the 95th percentile recovery time.
+	P95 time.Duration `json:"p95"`
+	// Max is the maximum recovery time.
+	Max time.Duration `json:"max"`
+}
+
+// AggregateRecovery computes the recovery time aggregates of each system from the timing of its scenarios. Compound
+// scenarios are aggregated separately for each combination of systems, since their recovery time is the time taken by
+// the slowest of the systems. Percentiles are calculated using nearest-rank method.
+func (r *Reporter) AggregateRecovery() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	recoveryTimes := make(map[string][]time.Duration)
+	compoundRecoveryTimes := make(map[string][]time.Duration)
+
+	for _, scenario := range r.Scenarios.Scenarios {
+		if scenario.Timing == nil || scenario.Timing.Recovered == nil {
+			continue
+		}
+
+		if scenario.Compound {
+			compoundRecoveryTimes[scenario.System] = append(compoundRecoveryTimes[scenario.System],
+				scenario.Timing.RecoveryTime)
+			continue
+		}
+
+		recoveryTimes[scenario.System] = append(recoveryTimes[scenario.System], scenario.Timing.RecoveryTime)
+	}
+
+	r.Recovery = aggregate(recoveryTimes)
+	r.CompoundRecovery = aggregate(compoundRecoveryTimes)
+}
+
+// aggregate returns the recovery aggregates of each of the keys of recoveryTimes, or nil if there are none.
+func aggregate(recoveryTimes map[string][]time.Duration) map[string]Recovery {
+	if len(recoveryTimes) == 0 {
+		return nil
+	}
+
+	recovery := make(map[string]Recovery, len(recoveryTimes))
+
+	for name, durations := range recoveryTimes {
+		sort.Slice(durations, func(i, j int) bool {
+			return durations[i] < durations[j]
+		})
+
+		recovery[name] = Recovery{
+			Scenarios: len(durations),
+			P50:       percentile(durations, 50),
+			P95:       percentile(durations, 95),
+			Max:       durations[len(durations)-1],
+		}
+	}
+
+	return recovery
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(durations []time.Duration, p int) time.Duration {
+	rank := (p*len(durations) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return durations[rank-1]
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateRecovery(t *testing.T) {
+	recovered := time.Now()
+
+	var scenarios []Scenario
+
+	for i := 1; i <= 20; i++ {
+		scenarios = append(scenarios, Scenario{
+			System: "system1",
+			Timing: &Timing{
+				Recovered:    &recovered,
+				RecoveryTime: time.Duration(i) * time.Second,
+			},
+		})
+	}
+
+	scenarios = append(scenarios,
+		Scenario{
+			System: "system1",
+			Timing: &Timing{},
+		},
+		Scenario{
+			System: "system2",
+			Timing: &Timing{
+				Recovered:    &recovered,
+				RecoveryTime: 3 * time.Second,
+			},
+		},
+		Scenario{
+			System: "system3",
+		},
+		Scenario{
+			System:   "system1,system2",
+			Compound: true,
+			Timing: &Timing{
+				Recovered:    &recovered,
+				RecoveryTime: 40 * time.Second,
+			},
+		},
+	)
+
+	reporter := &Reporter{
+		Scenarios: Scenarios{
+			Scenarios: scenarios,
+		},
+	}
+
+	reporter.AggregateRecovery()
+	require.Equal(t, map[string]Recovery{
+		"system1": {
+			Scenarios: 20,
+			P50:       10 * time.Second,
+			P95:       19 * time.Second,
+			Max:       20 * time.Second,
+		},
+		"system2": {
+			Scenarios: 1,
+			P50:       3 * time.Second,
+			P95:       3 * time.Second,
+			Max:       3 * time.Second,
+		},
+	}, reporter.Recovery)
+	require.Equal(t, map[string]Recovery{
+		"system1,system2": {
+			Scenarios: 1,
+			P50:       40 * time.Second,
+			P95:       40 * time.Second,
+			Max:       40 * time.Second,
+		},
+	}, reporter.CompoundRecovery)
+}
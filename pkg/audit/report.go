@@ -3,6 +3,8 @@ package audit
 import (
 	"encoding/json"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -17,6 +19,14 @@ type Reporter struct {
 	Scenarios `json:"scenarios"`
 	// Miscellaneous contains any other information.
 	Miscellaneous []Message `json:"miscellaneous"`
+	// Seeds contains the seed used to generate random scenarios of each system.
+	Seeds map[string]int64 `json:"seeds,omitempty"`
+	// Recovery contains the aggregates of recovery time of scenarios of each system.
+	Recovery map[string]Recovery `json:"recovery,omitempty"`
+	// CompoundRecovery contains the aggregates of recovery time of compound scenarios of each combination of systems.
+	CompoundRecovery map[string]Recovery `json:"compound_recovery,omitempty"`
+
+	mx sync.Mutex
 }
 
 // Message is the basic message structure in chaos test report.
@@ -25,6 +35,10 @@ type Message struct {
 	Result string `json:"result"`
 	// Message gives more context to the user about what happened.
 	Message string `json:"message"`
+	// Duration is the time taken by the step which is reported.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Output is the output captured while the step was executed, such as stdout and stderr of a command.
+	Output string `json:"output,omitempty"`
 }
 
 // Ready contains the report information of ready phase.
@@ -49,12 +63,76 @@ type Load struct {
 
 // Scenario represents a single chaos test scenario.
 type Scenario struct {
+	// System is the name of the system on which chaos test scenario is executed. Names of all systems involved are
+	// separated by comma for compound scenarios.
+	System string `json:"system,omitempty"`
+	// Compound indicates whether chaos test scenario involves multiple systems.
+	Compound bool `json:"compound,omitempty"`
 	// Identifiers indicate the identifiers involved in the chaos test scenario.
 	Identifiers string `json:"identifiers"`
+	// Restored contains the identifiers which had to be restored as system failed to recover by itself.
+	Restored []string `json:"restored,omitempty"`
+	// Timing contains the timestamps of chaos test scenario execution.
+	Timing *Timing `json:"timing,omitempty"`
+	// Drift lists the deviations from desired state of system which failed to recover.
+	Drift []Drift `json:"drift,omitempty"`
+	// SteadyState contains report information of steady state checks around chaos test scenario.
+	SteadyState *SteadyState `json:"steady_state,omitempty"`
+	// Hooks contains report information of hooks executed around chaos test scenario.
+	Hooks *ScenarioHooks `json:"hooks,omitempty"`
 	// Message contains report information of chaos test scenario.
 	Message
 }
 
+// ScenarioHooks contains the report information of hooks executed around chaos test scenario. Hooks which weren't
+// executed are left out.
+type ScenarioHooks struct {
+	// PreScenario contains report information of pre scenario hook.
+	PreScenario *Message `json:"pre_scenario,omitempty"`
+	// PostScenario contains report information of post scenario hook.
+	PostScenario *Message `json:"post_scenario,omitempty"`
+	// ScenarioFailure contains report information of scenario failure hook.
+	ScenarioFailure *Message `json:"scenario_failure,omitempty"`
+}
+
+// SteadyState contains the report information of steady state checks executed before and after chaos test scenario.
+type SteadyState struct {
+	// Before contains report information of steady state check executed before kill.
+	Before Message `json:"before"`
+	// After contains report information of steady state check executed after recovery. It is not set if scenario
+	// didn't get that far.
+	After *Message `json:"after,omitempty"`
+}
+
+// Timing contains the timestamps of chaos test scenario execution. It is used to measure how long system takes to
+// recover from chaos.
+type Timing struct {
+	// KillStart is the time at which killing of identifiers started.
+	KillStart time.Time `json:"kill_start"`
+	// KillEnd is the time at which killing of identifiers completed.
+	KillEnd time.Time `json:"kill_end"`
+	// FirstValidation is the time at which system was validated first after kill.
+	FirstValidation *time.Time `json:"first_validation,omitempty"`
+	// Recovered is the time at which system was found to be in desired state. It is not set if system didn't recover.
+	Recovered *time.Time `json:"recovered,omitempty"`
+	// RecoveryTime is the time taken by system to get back into desired state after kill completed.
+	RecoveryTime time.Duration `json:"recovery_time,omitempty"`
+	// ValidationAttempts is the number of times system was validated.
+	ValidationAttempts int `json:"validation_attempts"`
+}
+
+// Drift describes how a single resource deviates from its desired state.
+type Drift struct {
+	// System is the name of system of the resource. It is set only for scenarios which involve multiple systems.
+	System string `json:"system,omitempty"`
+	// ID of the resource which drifted.
+	ID string `json:"id"`
+	// Type classifies the drift such as missing, extra, label, status or condition.
+	Type string `json:"type"`
+	// Message describes the drift in detail.
+	Message string `json:"message"`
+}
+
 // Scenarios contains the report information of actual chaos testing phase.
 type Scenarios struct {
 	// PreChaosTests contains report information of pre chaos test hook.
@@ -65,8 +143,27 @@ type Scenarios struct {
 	Scenarios []Scenario `json:"scenarios"`
 }
 
+// AddScenario adds the report information of a chaos test scenario. It is safe to be called concurrently.
+func (r *Reporter) AddScenario(scenario Scenario) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.Scenarios.Scenarios = append(r.Scenarios.Scenarios, scenario)
+}
+
+// AddMiscellaneous adds miscellaneous information to report. It is safe to be called concurrently.
+func (r *Reporter) AddMiscellaneous(message Message) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.Miscellaneous = append(r.Miscellaneous, message)
+}
+
 // Report writes the json representation of Reporter into the writer passed.
 func (r *Reporter) Report(writer io.Writer) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
 	report, err := json.Marshal(r)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshall report")
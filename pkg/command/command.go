@@ -0,0 +1,170 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	execKey        = "exec"
+	commandKey     = "command"
+	envKey         = "env"
+	dirKey         = "dir"
+	timeoutKey     = "timeout"
+	strTypeErrMsg  = "field '%s' should be of type string"
+	maxOutputBytes = 64 * 1024
+)
+
+// Command is a local command which is executed by exec ready condition and exec hook.
+type Command struct {
+	args    []string
+	env     []string
+	dir     string
+	timeout time.Duration
+}
+
+// Parse parses the definition of command under exec field.
+func Parse(conf map[string]interface{}) (*Command, error) {
+	execConf, ok := conf[execKey].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("field '%s' should be of type map", execKey)
+	}
+
+	argsValue, ok := execConf[commandKey].([]interface{})
+	if !ok || len(argsValue) == 0 {
+		return nil, errors.Errorf("field '%s' under '%s' should be of type non-empty array", commandKey, execKey)
+	}
+
+	command := &Command{}
+
+	for _, argValue := range argsValue {
+		arg, ok := argValue.(string)
+		if !ok {
+			return nil, errors.Errorf("arguments of '%s' should be of type string", commandKey)
+		}
+
+		command.args = append(command.args, arg)
+	}
+
+	if envValue, ok := execConf[envKey]; ok {
+		env, ok := envValue.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type map", envKey)
+		}
+
+		for name, value := range env {
+			envVar, ok := value.(string)
+			if !ok {
+				return nil, errors.Errorf("environment variable '%s' should be of type string", name)
+			}
+
+			command.env = append(command.env, fmt.Sprintf("%s=%s", name, envVar))
+		}
+	}
+
+	if dirValue, ok := execConf[dirKey]; ok {
+		dir, ok := dirValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, dirKey)
+		}
+
+		command.dir = dir
+	}
+
+	if timeoutValue, ok := execConf[timeoutKey]; ok {
+		timeoutStr, ok := timeoutValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, timeoutKey)
+		}
+
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse '%s'", timeoutKey)
+		}
+
+		command.timeout = timeout
+	}
+
+	return command, nil
+}
+
+// Run runs the command with env added to its environment and returns its combined stdout and stderr. Output beyond
+// 64KiB is truncated. Error is returned if command couldn't be started, it timed out or it exited with non-zero exit
+// code, in which case the error is of type *exec.ExitError.
+func (c *Command) Run(ctx context.Context, env ...string) (string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	out := &limitedBuffer{limit: maxOutputBytes}
+
+	cmd := exec.CommandContext(ctx, c.args[0], c.args[1:]...)
+	cmd.Env = append(append(os.Environ(), c.env...), env...)
+	cmd.Dir = c.dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return out.String(), errors.Wrapf(ctx.Err(), "command '%s' didn't complete", c.args[0])
+	}
+
+	return out.String(), err
+}
+
+// String returns the command line of command.
+func (c *Command) String() string {
+	return fmt.Sprint(c.args)
+}
+
+// limitedBuffer is a buffer which discards whatever is written beyond its limit.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write writes p into buffer as long as it's within limit and always reports p as written so that command isn't
+// interrupted.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buffer.Len(); len(p) > remaining {
+		b.truncated = true
+		_, _ = b.buffer.Write(p[:remaining])
+
+		return len(p), nil
+	}
+
+	return b.buffer.Write(p)
+}
+
+// String returns the contents of buffer noting whether it was truncated.
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buffer.String() + "\n... output truncated"
+	}
+
+	return b.buffer.String()
+}
@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		description string
+		conf        string
+		output      string
+		expectedErr bool
+		parseErr    bool
+	}{
+		{
+			description: "successful command",
+			conf:        "command: [sh, -c, 'echo out; echo err >&2']",
+			output:      "out\nerr\n",
+		},
+		{
+			description: "environment variables",
+			conf:        "command: [sh, -c, 'echo $LOKI_TEST']\nenv:\n  LOKI_TEST: value",
+			output:      "value\n",
+		},
+		{
+			description: "working directory",
+			conf:        "command: [pwd]\ndir: /",
+			output:      "/\n",
+		},
+		{
+			description: "failed command",
+			conf:        "command: [sh, -c, 'echo failed; exit 3']",
+			output:      "failed\n",
+			expectedErr: true,
+		},
+		{
+			description: "timed out command",
+			conf:        "command: [sleep, '5']\ntimeout: 100ms",
+			expectedErr: true,
+		},
+		{
+			description: "missing command",
+			conf:        "env:\n  LOKI_TEST: value",
+			parseErr:    true,
+		},
+		{
+			description: "malformed argument",
+			conf:        "command: [echo, [value]]",
+			parseErr:    true,
+		},
+		{
+			description: "malformed timeout",
+			conf:        "command: [echo]\ntimeout: soon",
+			parseErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			command, err := Parse(execConf(t, test.conf))
+			if test.parseErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			output, err := command.Run(context.Background())
+			if test.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, test.output, output)
+		})
+	}
+}
+
+func TestCommandOutputLimit(t *testing.T) {
+	command, err := Parse(execConf(t, "command: [head, -c, '100000', /dev/zero]"))
+	require.NoError(t, err)
+
+	output, err := command.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, strings.HasSuffix(output, "output truncated"))
+	require.Equal(t, true, len(output) < 2*maxOutputBytes)
+}
+
+func TestReadyCond(t *testing.T) {
+	parser := NewReadyParser(loki.NewConfig())
+
+	readyCond, err := parser.Parse(execConf(t, "command: ['true']"))
+	require.NoError(t, err)
+
+	ready, err := readyCond.Ready(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, ready)
+
+	readyCond, err = parser.Parse(execConf(t, "command: ['false']"))
+	require.NoError(t, err)
+
+	ready, err = readyCond.Ready(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, false, ready)
+
+	readyCond, err = parser.Parse(execConf(t, "command: [/non/existent/command]"))
+	require.NoError(t, err)
+
+	_, err = readyCond.Ready(context.Background())
+	require.Error(t, err)
+}
+
+func TestHook(t *testing.T) {
+	parser := NewHookParser(loki.NewConfig())
+
+	hook, err := parser.Parse(execConf(t, "command: ['true']"))
+	require.NoError(t, err)
+	require.NoError(t, hook(context.Background()))
+
+	hook, err = parser.Parse(execConf(t, "command: ['false']"))
+	require.NoError(t, err)
+	require.Error(t, hook(context.Background()))
+}
+
+func execConf(t *testing.T, conf string) map[string]interface{} {
+	commandConf := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(conf), &commandConf)
+	require.NoError(t, err)
+
+	return map[string]interface{}{execKey: commandConf}
+}
@@ -0,0 +1,78 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+const (
+	scenarioSystemEnv      = "LOKI_SCENARIO_SYSTEM"
+	scenarioIndexEnv       = "LOKI_SCENARIO_INDEX"
+	scenarioIdentifiersEnv = "LOKI_SCENARIO_IDENTIFIERS"
+)
+
+// NewHookParser instantiates HookParser which parses hooks running exec command. Hook fails if command doesn't exit
+// with exit code 0. Scenario hooks pass the description of scenario to command through LOKI_SCENARIO_SYSTEM,
+// LOKI_SCENARIO_INDEX and LOKI_SCENARIO_IDENTIFIERS environment variables, the last one having an identifier per line.
+func NewHookParser(*loki.Config) loki.HookParser {
+	return loki.HookParserFunc(func(hookConf map[string]interface{}) (func(context.Context) error, error) {
+		command, err := Parse(hookConf)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) error {
+			out, err := command.Run(ctx, scenarioEnv(ctx)...)
+			loki.RecordOutput(ctx, out)
+
+			if err != nil {
+				return errors.Wrapf(err, "failed to run command %s", command)
+			}
+
+			return nil
+		}, nil
+	})
+}
+
+// scenarioEnv returns the environment variables describing scenario of scenario hook.
+func scenarioEnv(ctx context.Context) []string {
+	info, ok := loki.ScenarioFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, 0, len(info.Identifiers))
+	for _, identifier := range info.Identifiers {
+		ids = append(ids, string(identifier.ID()))
+	}
+
+	return []string{
+		fmt.Sprintf("%s=%s", scenarioSystemEnv, info.System),
+		fmt.Sprintf("%s=%d", scenarioIndexEnv, info.Index),
+		fmt.Sprintf("%s=%s", scenarioIdentifiersEnv, strings.Join(ids, "\n")),
+	}
+}
+
+// RegisterHookParser registers exec HookParser into loki.
+func RegisterHookParser() {
+	loki.RegisterHookParser(execKey, NewHookParser)
+}
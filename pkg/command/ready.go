@@ -0,0 +1,64 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+// ReadyCond performs readiness check by running a local command. Systems are ready when command exits with exit code 0.
+type ReadyCond struct {
+	command *Command
+}
+
+// NewReadyParser instantiates ReadyParser which parses ready section using exec command.
+func NewReadyParser(*loki.Config) loki.ReadyParser {
+	return loki.ReadyParserFunc(func(readyConf map[string]interface{}) (loki.ReadyCond, error) {
+		command, err := Parse(readyConf)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ReadyCond{
+			command: command,
+		}, nil
+	})
+}
+
+// Ready runs the command and checks whether it exited successfully. Output of command is recorded into report.
+func (r *ReadyCond) Ready(ctx context.Context) (bool, error) {
+	out, err := r.command.Run(ctx)
+	loki.RecordOutput(ctx, out)
+
+	if _, ok := err.(*exec.ExitError); ok {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to run command %s", r.command)
+	}
+
+	return true, nil
+}
+
+// RegisterReadyParser registers exec ReadyParser into loki.
+func RegisterReadyParser() {
+	loki.RegisterReadyParser(execKey, NewReadyParser)
+}
@@ -0,0 +1,75 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+// NewHookParser instantiates HookParser which parses hooks sending http request, for example to notify other tools or
+// to trigger collection of diagnostics. Hook fails if endpoint doesn't respond with expected status, or with a 2xx
+// status when expected status isn't defined.
+func NewHookParser(*loki.Config) loki.HookParser {
+	return loki.HookParserFunc(func(hookConf map[string]interface{}) (func(context.Context) error, error) {
+		httpConf, ok := hookConf[httpKey].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type map", httpKey)
+		}
+
+		hookRequest, err := parseRequest(httpConf, http.MethodPost)
+		if err != nil {
+			return nil, err
+		}
+
+		expectedStatus := 0
+		if _, ok := httpConf[expectedStatusKey]; ok {
+			if expectedStatus, err = parseExpectedStatus(httpConf); err != nil {
+				return nil, err
+			}
+		}
+
+		return func(ctx context.Context) error {
+			status, body, err := hookRequest.do(ctx)
+			if err != nil {
+				return err
+			}
+
+			loki.RecordOutput(ctx, fmt.Sprintf("%s %s responded with status %d\n%s",
+				hookRequest.method, hookRequest.url, status, body))
+
+			unexpected := status < 200 || status > 299
+			if expectedStatus != 0 {
+				unexpected = status != expectedStatus
+			}
+
+			if unexpected {
+				return errors.Errorf("'%s' responded with unexpected status %d", hookRequest.url, status)
+			}
+
+			return nil
+		}, nil
+	})
+}
+
+// RegisterHookParser registers http HookParser into loki.
+func RegisterHookParser() {
+	loki.RegisterHookParser(httpKey, NewHookParser)
+}
@@ -0,0 +1,95 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestHook(t *testing.T) {
+	var method, body, header string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		method, body, header = r.Method, string(payload), r.Header.Get("X-Loki")
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		conf        string
+		method      string
+		expectedErr bool
+	}{
+		{
+			description: "default method",
+			conf:        "url: $URL/notify\nbody: chaos started\nheaders:\n  X-Loki: hook",
+			method:      http.MethodPost,
+		},
+		{
+			description: "custom method",
+			conf:        "url: $URL/notify\nmethod: put\nbody: chaos started\nheaders:\n  X-Loki: hook",
+			method:      http.MethodPut,
+		},
+		{
+			description: "unsuccessful status",
+			conf:        "url: $URL/missing\nbody: chaos started\nheaders:\n  X-Loki: hook",
+			method:      http.MethodPost,
+			expectedErr: true,
+		},
+		{
+			description: "expected status",
+			conf:        "url: $URL/missing\nbody: chaos started\nheaders:\n  X-Loki: hook\nexpectedStatus: 404",
+			method:      http.MethodPost,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			httpConf := make(map[string]interface{})
+			err := yaml.Unmarshal([]byte(strings.ReplaceAll(test.conf, "$URL", server.URL)), &httpConf)
+			require.NoError(t, err)
+
+			hook, err := NewHookParser(loki.NewConfig()).Parse(map[string]interface{}{httpKey: httpConf})
+			require.NoError(t, err)
+
+			err = hook(context.Background())
+			if test.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, test.method, method)
+			require.Equal(t, "chaos started", body)
+			require.Equal(t, "hook", header)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+const (
+	httpKey               = "http"
+	urlKey                = "url"
+	methodKey             = "method"
+	headersKey            = "headers"
+	bodyKey               = "body"
+	expectedStatusKey     = "expectedStatus"
+	bodyRegexKey          = "bodyRegex"
+	jsonPathKey           = "jsonPath"
+	jsonValueKey          = "jsonValue"
+	insecureSkipVerifyKey = "insecureSkipVerify"
+	caFileKey             = "caFile"
+	timeoutKey            = "timeout"
+	strTypeErrMsg         = "field '%s' should be of type string"
+	defaultTimeout        = 10 * time.Second
+	maxBodyBytes          = 64 * 1024
+)
+
+// ReadyParser parses the ready section to create ReadyCond which identifies readiness by probing an http endpoint.
+type ReadyParser struct {
+	config *loki.Config
+}
+
+// NewReadyParser instantiates ReadyParser.
+func NewReadyParser(config *loki.Config) loki.ReadyParser {
+	return &ReadyParser{
+		config: config,
+	}
+}
+
+// Parse parses the ready section which uses http probe and creates ReadyCond.
+func (parser *ReadyParser) Parse(readyConf map[string]interface{}) (loki.ReadyCond, error) {
+	httpConf, ok := readyConf[httpKey].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("field '%s' should be of type map", httpKey)
+	}
+
+	probe, err := parseRequest(httpConf, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+
+	readyCond := &ReadyCond{
+		request:        probe,
+		expectedStatus: http.StatusOK,
+	}
+
+	if _, ok := httpConf[expectedStatusKey]; ok {
+		status, err := parseExpectedStatus(httpConf)
+		if err != nil {
+			return nil, err
+		}
+
+		readyCond.expectedStatus = status
+	}
+
+	if bodyRegexValue, ok := httpConf[bodyRegexKey]; ok {
+		bodyRegex, ok := bodyRegexValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, bodyRegexKey)
+		}
+
+		compiled, err := regexp.Compile(bodyRegex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile '%s'", bodyRegexKey)
+		}
+
+		readyCond.bodyRegex = compiled
+	}
+
+	if err := parseJSONPath(httpConf, readyCond); err != nil {
+		return nil, err
+	}
+
+	return readyCond, nil
+}
+
+func parseJSONPath(httpConf map[string]interface{}, readyCond *ReadyCond) error {
+	jsonPathValue, ok := httpConf[jsonPathKey]
+	if !ok {
+		if _, ok := httpConf[jsonValueKey]; ok {
+			return errors.Errorf("field '%s' requires field '%s'", jsonValueKey, jsonPathKey)
+		}
+
+		return nil
+	}
+
+	path, ok := jsonPathValue.(string)
+	if !ok {
+		return errors.Errorf(strTypeErrMsg, jsonPathKey)
+	}
+
+	if err := jsonpath.New(jsonPathKey).Parse(path); err != nil {
+		return errors.Wrapf(err, "failed to parse '%s'", jsonPathKey)
+	}
+
+	readyCond.jsonPath = path
+
+	if jsonValue, ok := httpConf[jsonValueKey]; ok {
+		value := fmt.Sprint(jsonValue)
+		readyCond.jsonValue = &value
+	}
+
+	return nil
+}
+
+// RegisterReadyParser registers http ReadyParser into loki.
+func RegisterReadyParser() {
+	loki.RegisterReadyParser(httpKey, NewReadyParser)
+}
@@ -0,0 +1,99 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ReadyCond performs readiness check by probing an http endpoint. It's safe to check readiness concurrently.
+type ReadyCond struct {
+	request        *request
+	expectedStatus int
+	bodyRegex      *regexp.Regexp
+	// jsonPath is the json path template, which is parsed for every check as parsed json path isn't safe for concurrent
+	// use.
+	jsonPath  string
+	jsonValue *string
+}
+
+// Ready checks whether endpoint responds with expected status and its body matches the body regex and json path
+// assertions if they're defined.
+func (r *ReadyCond) Ready(ctx context.Context) (bool, error) {
+	status, body, err := r.request.do(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to probe")
+	}
+
+	if status != r.expectedStatus {
+		return false, nil
+	}
+
+	if r.bodyRegex != nil && !r.bodyRegex.Match(body) {
+		return false, nil
+	}
+
+	if r.jsonPath != "" {
+		return r.matchJSONPath(body), nil
+	}
+
+	return true, nil
+}
+
+// matchJSONPath checks whether json path matches anything in body and its value, printed the way kubectl prints json
+// path, is same as the expected value if it is defined.
+func (r *ReadyCond) matchJSONPath(body []byte) bool {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return false
+	}
+
+	jsonPath := jsonpath.New(jsonPathKey)
+	if err := jsonPath.Parse(r.jsonPath); err != nil {
+		return false
+	}
+
+	results, err := jsonPath.FindResults(data)
+	if err != nil {
+		return false
+	}
+
+	value := &bytes.Buffer{}
+	found := false
+
+	for _, result := range results {
+		if len(result) == 0 {
+			continue
+		}
+
+		found = true
+
+		if err := jsonPath.PrintResults(value, result); err != nil {
+			return false
+		}
+	}
+
+	if !found {
+		return false
+	}
+
+	return r.jsonValue == nil || value.String() == *r.jsonValue
+}
@@ -0,0 +1,284 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestReadyCond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, `{"status": "UP", "checks": [{"name": "db", "ready": true}], "replicas": 3}`)
+		case "/secured":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			fmt.Fprint(w, "ok")
+		case "/starting":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		conf        string
+		ready       bool
+		expectedErr bool
+		parseErr    bool
+	}{
+		{
+			description: "expected status",
+			conf:        "url: $URL/health",
+			ready:       true,
+		},
+		{
+			description: "unexpected status",
+			conf:        "url: $URL/starting",
+		},
+		{
+			description: "custom expected status",
+			conf:        "url: $URL/starting\nexpectedStatus: 503",
+			ready:       true,
+		},
+		{
+			description: "headers",
+			conf:        "url: $URL/secured\nheaders:\n  Authorization: Bearer token",
+			ready:       true,
+		},
+		{
+			description: "missing headers",
+			conf:        "url: $URL/secured",
+		},
+		{
+			description: "matching body regex",
+			conf:        "url: $URL/health\nbodyRegex: '\"status\": *\"UP\"'",
+			ready:       true,
+		},
+		{
+			description: "non matching body regex",
+			conf:        "url: $URL/health\nbodyRegex: DOWN",
+		},
+		{
+			description: "existing json path",
+			conf:        "url: $URL/health\njsonPath: '{.checks[0].name}'",
+			ready:       true,
+		},
+		{
+			description: "missing json path",
+			conf:        "url: $URL/health\njsonPath: '{.version}'",
+		},
+		{
+			description: "matching json value",
+			conf:        "url: $URL/health\njsonPath: '{.status}'\njsonValue: UP",
+			ready:       true,
+		},
+		{
+			description: "matching non string json value",
+			conf:        "url: $URL/health\njsonPath: '{.checks[?(@.name==\"db\")].ready}'\njsonValue: true",
+			ready:       true,
+		},
+		{
+			description: "matching numeric json value",
+			conf:        "url: $URL/health\njsonPath: '{.replicas}'\njsonValue: 3",
+			ready:       true,
+		},
+		{
+			description: "non matching json value",
+			conf:        "url: $URL/health\njsonPath: '{.status}'\njsonValue: DOWN",
+		},
+		{
+			description: "unreachable endpoint",
+			conf:        "url: http://127.0.0.1:1/health",
+			expectedErr: true,
+		},
+		{
+			description: "missing url",
+			conf:        "method: GET",
+			parseErr:    true,
+		},
+		{
+			description: "malformed body regex",
+			conf:        "url: $URL/health\nbodyRegex: '('",
+			parseErr:    true,
+		},
+		{
+			description: "json value without json path",
+			conf:        "url: $URL/health\njsonValue: UP",
+			parseErr:    true,
+		},
+		{
+			description: "invalid status",
+			conf:        "url: $URL/health\nexpectedStatus: 42",
+			parseErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			readyCond, err := parse(t, strings.ReplaceAll(test.conf, "$URL", server.URL))
+			if test.parseErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			ready, err := readyCond.Ready(context.Background())
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.ready, ready)
+		})
+	}
+}
+
+func TestReadyCondTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	caFile, err := ioutil.TempFile("", "ca-*.pem")
+	require.NoError(t, err)
+	defer os.Remove(caFile.Name())
+
+	err = pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, err)
+	require.NoError(t, caFile.Close())
+
+	readyCond, err := parse(t, fmt.Sprintf("url: %s\ntimeout: 2s", server.URL))
+	require.NoError(t, err)
+
+	_, err = readyCond.Ready(context.Background())
+	require.Error(t, err)
+
+	readyCond, err = parse(t, fmt.Sprintf("url: %s\ninsecureSkipVerify: true", server.URL))
+	require.NoError(t, err)
+
+	ready, err := readyCond.Ready(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, ready)
+
+	readyCond, err = parse(t, fmt.Sprintf("url: %s\ncaFile: %s", server.URL, caFile.Name()))
+	require.NoError(t, err)
+
+	ready, err = readyCond.Ready(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, ready)
+}
+
+func TestReadyCondConcurrentSteadyState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"checks": [{"name": "db", "ready": true}, {"name": "cache", "ready": true}]}`)
+	}))
+	defer server.Close()
+
+	RegisterReadyParser()
+	loki.RegisterReadyParser("after", loki.AfterParser)
+	loki.RegisterSystem("steady-http-test-system", func() loki.System {
+		return &loki.TestSystem{
+			Resources: make(map[loki.TestIdentifier]bool),
+			State:     make(map[loki.TestIdentifier]bool),
+		}
+	})
+	loki.RegisterDestroyer("steady-http-test-system", loki.DestroyerTest())
+	loki.RegisterKiller("steady-http-test-system", func(system loki.System) (loki.Killer, error) {
+		return loki.KillerFunc(func(context.Context, ...loki.Identifier) error {
+			return nil
+		}), nil
+	})
+
+	conf := `
+ready:
+  after: 0s
+steadyState:
+  http:
+    url: %s
+    jsonPath: '{.checks[?(@.name=="db")].ready}'
+    jsonValue: true
+systems:
+- type: steady-http-test-system
+  name: system1
+  resources:
+  - resource1
+- type: steady-http-test-system
+  name: system2
+  resources:
+  - resource1
+run:
+  concurrency: 2
+destroy:
+  scenarios:
+  - system: system1
+    resources:
+    - resource1
+  - system: system1
+    resources:
+    - resource1
+  - system: system2
+    resources:
+    - resource1
+  - system: system2
+    resources:
+    - resource1
+`
+	configuration := loki.NewConfig()
+
+	err := configuration.Parse([]byte(fmt.Sprintf(conf, server.URL)))
+	require.NoError(t, err)
+
+	chaosMaker := &loki.ChaosMaker{
+		Config:      configuration,
+		FieldLogger: logrus.New(),
+	}
+
+	// steady state is checked by both systems concurrently, which is reported by race detector if it isn't safe.
+	err = chaosMaker.CreateChaos(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, len(chaosMaker.Reporter.Scenarios.Scenarios))
+}
+
+func parse(t *testing.T, conf string) (loki.ReadyCond, error) {
+	httpConf := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(conf), &httpConf)
+	require.NoError(t, err)
+
+	return NewReadyParser(loki.NewConfig()).Parse(map[string]interface{}{httpKey: httpConf})
+}
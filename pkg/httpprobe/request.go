@@ -0,0 +1,202 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// request is the http request sent by http ready condition and http hook.
+type request struct {
+	url     string
+	method  string
+	headers map[string]string
+	body    string
+	client  *http.Client
+}
+
+// parseRequest parses the request fields common to http ready condition and http hook.
+func parseRequest(httpConf map[string]interface{}, defaultMethod string) (*request, error) {
+	urlValue, ok := httpConf[urlKey]
+	if !ok {
+		return nil, errors.Errorf("field '%s' is mandatory under '%s'", urlKey, httpKey)
+	}
+
+	requestURL, ok := urlValue.(string)
+	if !ok {
+		return nil, errors.Errorf(strTypeErrMsg, urlKey)
+	}
+
+	if _, err := url.ParseRequestURI(requestURL); err != nil {
+		return nil, errors.Wrapf(err, "invalid url '%s'", requestURL)
+	}
+
+	req := &request{
+		url:     requestURL,
+		method:  defaultMethod,
+		headers: make(map[string]string),
+	}
+
+	if methodValue, ok := httpConf[methodKey]; ok {
+		method, ok := methodValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, methodKey)
+		}
+
+		req.method = strings.ToUpper(method)
+	}
+
+	if headersValue, ok := httpConf[headersKey]; ok {
+		headers, ok := headersValue.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type map", headersKey)
+		}
+
+		for name, value := range headers {
+			header, ok := value.(string)
+			if !ok {
+				return nil, errors.Errorf("header '%s' should be of type string", name)
+			}
+
+			req.headers[name] = header
+		}
+	}
+
+	if bodyValue, ok := httpConf[bodyKey]; ok {
+		body, ok := bodyValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, bodyKey)
+		}
+
+		req.body = body
+	}
+
+	client, err := parseClient(httpConf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.client = client
+
+	return req, nil
+}
+
+// parseExpectedStatus parses the status code with which endpoint is expected to respond.
+func parseExpectedStatus(httpConf map[string]interface{}) (int, error) {
+	status, ok := httpConf[expectedStatusKey].(float64)
+	if !ok || status != float64(int(status)) || status < 100 || status > 599 {
+		return 0, errors.Errorf("field '%s' should be a valid http status code", expectedStatusKey)
+	}
+
+	return int(status), nil
+}
+
+// do sends the request and returns the status code and body of response. Body beyond maxBodyBytes is discarded.
+func (r *request) do(ctx context.Context) (int, []byte, error) {
+	var body io.Reader
+	if r.body != "" {
+		body = strings.NewReader(r.body)
+	}
+
+	httpRequest, err := http.NewRequest(r.method, r.url, body)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to create request to '%s'", r.url)
+	}
+
+	httpRequest = httpRequest.WithContext(ctx)
+
+	for name, value := range r.headers {
+		httpRequest.Header.Set(name, value)
+	}
+
+	response, err := r.client.Do(httpRequest)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to send request to '%s'", r.url)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, maxBodyBytes))
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to read response of '%s'", r.url)
+	}
+
+	return response.StatusCode, responseBody, nil
+}
+
+func parseClient(httpConf map[string]interface{}) (*http.Client, error) {
+	timeout := defaultTimeout
+
+	if timeoutValue, ok := httpConf[timeoutKey]; ok {
+		timeoutStr, ok := timeoutValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, timeoutKey)
+		}
+
+		var err error
+
+		timeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse '%s'", timeoutKey)
+		}
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if skipValue, ok := httpConf[insecureSkipVerifyKey]; ok {
+		skip, ok := skipValue.(bool)
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type bool", insecureSkipVerifyKey)
+		}
+
+		tlsConfig.InsecureSkipVerify = skip
+	}
+
+	if caFileValue, ok := httpConf[caFileKey]; ok {
+		caFile, ok := caFileValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, caFileKey)
+		}
+
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read ca file '%s'", caFile)
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("no certificates found in ca file '%s'", caFile)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
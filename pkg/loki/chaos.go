@@ -16,6 +16,10 @@ package loki
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,11 +36,18 @@ type ChaosMaker struct {
 	*audit.Reporter
 }
 
-// CreateChaos executes all the chaos scenarios and exits with error on first scenario which fails to recover and
-// get into desired state or returns successfully if all systems get back into desired state from all chaos scenarios.
+// CreateChaos executes all the chaos scenarios and returns successfully if all systems get back into desired state from
+// all chaos scenarios. Scenarios which fail to recover and get into desired state are handled as per the run policy of
+// Config, by default execution stops on first failed scenario. Errors of all failed scenarios are returned as
+// ScenarioFailures. Execution stops irrespective of run policy once ctx is done, and the scenario in flight is reported
+// as aborted. Hooks defined in hooks section of Config are overridden by the ones passed for same phase.
 func (cm *ChaosMaker) CreateChaos(ctx context.Context, opts ...HookOption) error {
 	hook := &Hook{}
 
+	for _, opt := range cm.hooks {
+		opt(hook)
+	}
+
 	for _, opt := range opts {
 		opt(hook)
 	}
@@ -45,6 +56,8 @@ func (cm *ChaosMaker) CreateChaos(ctx context.Context, opts ...HookOption) error
 		cm.Reporter = &audit.Reporter{}
 	}
 
+	defer cm.Reporter.AggregateRecovery()
+
 	if err := cm.readyCheck(ctx, hook); err != nil {
 		return err
 	}
@@ -54,212 +67,757 @@ func (cm *ChaosMaker) CreateChaos(ctx context.Context, opts ...HookOption) error
 	}
 
 	if hook.preChaos != nil {
-		cm.Info("pre chaos hook executing")
-		result := audit.SuccessResult
-		message := "Successfully completed pre chaos test hook"
-
-		if err := hook.preChaos(ctx); err != nil {
-			result = audit.FailureResult
-			message = errors.Wrap(err, "pre chaos test hook failed").Error()
-			cm.WithError(err).Warn("pre chaos hook failed")
-		}
-
-		cm.Reporter.Scenarios.PreChaosTests = audit.Message{
-			Result:  result,
-			Message: message,
-		}
+		cm.Reporter.Scenarios.PreChaosTests = cm.runHook(ctx, "pre chaos test", hook.preChaos)
 	}
 
 	if hook.postChaos != nil {
 		defer func() {
-			cm.Info("post chaos hook executing")
-			result := audit.SuccessResult
-			message := "Successfully completed post chaos test hook"
-
-			if err := hook.postChaos(ctx); err != nil {
-				result = audit.FailureResult
-				message = errors.Wrap(err, "post chaos test hook failed").Error()
-				cm.WithError(err).Warn("post chaos hook failed")
-			}
+			cm.Reporter.Scenarios.PostChaosTests = cm.runHook(ctx, "post chaos test", hook.postChaos)
+		}()
+	}
 
-			cm.Reporter.Scenarios.PostChaosTests = audit.Message{
-				Result:  result,
-				Message: message,
-			}
+	targets, err := cm.chaosTargets()
+	if err != nil {
+		return err
+	}
+
+	run := &chaosRun{hook: hook}
+	stopCheckpoints := cm.startCheckpoints(ctx, hook)
+	// systems run one at a time unless concurrency is set, as a zero capacity would block every system.
+	concurrency := cm.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	workers := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for _, target := range targets {
+		target := target
+		if target.provider == nil {
+			continue
+		}
+
+		workers <- struct{}{}
+
+		if run.done(ctx) {
+			<-workers
+			break
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			cm.runSystem(ctx, run, target)
 		}()
 	}
 
-	for systemName, provider := range cm.scenarioProviders {
-		cm.Infof("creating chaos in '%s' system", systemName)
+	wg.Wait()
+
+	cm.runCompound(ctx, run, targets)
+	stopCheckpoints()
+
+	if run.err != nil {
+		return run.err
+	}
+
+	failures := run.failures
+
+	if ctx.Err() != nil {
+		abortErr := errors.Wrap(ctx.Err(), "chaos execution aborted")
+
+		cm.Reporter.AddMiscellaneous(audit.Message{
+			Result:  audit.AbortedResult,
+			Message: abortErr.Error(),
+		})
 
+		return abortErr
+	}
+
+	if len(failures) > 0 {
+		cm.Reporter.AddMiscellaneous(audit.Message{
+			Result:  audit.FailureResult,
+			Message: fmt.Sprintf("%d scenario(s) failed", len(failures)),
+		})
+
+		return failures
+	}
+
+	degraded := 0
+	for _, scenario := range cm.Reporter.Scenarios.Scenarios {
+		if scenario.Result == audit.DegradedResult {
+			degraded++
+		}
+	}
+
+	if degraded > 0 {
+		cm.Reporter.AddMiscellaneous(audit.Message{
+			Result:  audit.DegradedResult,
+			Message: fmt.Sprintf("%d scenario(s) recovered later than their recovery objective", degraded),
+		})
+	}
+
+	cm.Reporter.AddMiscellaneous(audit.Message{
+		Result:  audit.SuccessResult,
+		Message: "Successfully executed all scenarios",
+	})
+
+	return nil
+}
+
+// chaosTarget is a system on which chaos scenarios are executed along with the killer of the system.
+type chaosTarget struct {
+	systemName string
+	systemType string
+	system     System
+	provider   *scenarioProvider
+	killer     Killer
+}
+
+// scenarioTarget is a system along with its identifiers which are killed by a chaos scenario.
+type scenarioTarget struct {
+	chaosTarget
+	identifiers Identifiers
+}
+
+// scenarioExecution is a chaos scenario bound to the systems on which it acts. Scenarios of a single system have one
+// target while compound scenarios have a target for each involved system.
+type scenarioExecution struct {
+	timeout           time.Duration
+	recoveryObjective time.Duration
+	targets           []scenarioTarget
+	// index is the position of scenario, starting from 1, among scenarios of its system or among compound scenarios.
+	index int
+	// hook contains the user defined actions executed around scenario.
+	hook *Hook
+	// steadyState holds the report information of steady state checks executed so far, if steady state is defined.
+	steadyState *audit.SteadyState
+	// hooks holds the report information of scenario hooks executed so far, if any scenario hook is defined.
+	hooks *audit.ScenarioHooks
+}
+
+// identifiers returns the representation of identifiers of all targets used in report and logs.
+func (e *scenarioExecution) identifiers() string {
+	if len(e.targets) == 1 {
+		return e.targets[0].identifiers.String()
+	}
+
+	parts := make([]string, 0, len(e.targets))
+	for _, target := range e.targets {
+		parts = append(parts, fmt.Sprintf("system '%s': %s", target.systemName, target.identifiers))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// info returns the description of scenario passed to scenario hooks.
+func (e *scenarioExecution) info() ScenarioInfo {
+	info := ScenarioInfo{
+		System: joinSystemNames(e.targets),
+		Index:  e.index,
+	}
+
+	for _, target := range e.targets {
+		info.Identifiers = append(info.Identifiers, target.identifiers...)
+	}
+
+	return info
+}
+
+// scenarioHooks returns the report information of scenario hooks, creating it on first use.
+func (e *scenarioExecution) scenarioHooks() *audit.ScenarioHooks {
+	if e.hooks == nil {
+		e.hooks = &audit.ScenarioHooks{}
+	}
+
+	return e.hooks
+}
+
+// joinSystemNames returns the comma separated names of systems of targets.
+func joinSystemNames(targets []scenarioTarget) string {
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.systemName)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// chaosRun holds the state of chaos execution shared by systems executed concurrently.
+type chaosRun struct {
+	hook     *Hook
+	mx       sync.Mutex
+	failures ScenarioFailures
+	stopped  bool
+	err      error
+}
+
+// fail records the failure of a scenario and returns the total number of failures so far.
+func (r *chaosRun) fail(err error) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.failures = append(r.failures, err)
+
+	return len(r.failures)
+}
+
+// stop stops execution of further scenarios in all systems. err, if not nil, is returned by chaos execution instead
+// of scenario failures.
+func (r *chaosRun) stop(err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.stopped = true
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// done returns whether further scenarios shouldn't be executed.
+func (r *chaosRun) done(ctx context.Context) bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.stopped || ctx.Err() != nil
+}
+
+// chaosTargets creates killers of all systems involved in chaos scenarios in the order of system names.
+func (cm *ChaosMaker) chaosTargets() ([]chaosTarget, error) {
+	involved := make(map[string]bool)
+	for systemName := range cm.scenarioProviders {
+		involved[systemName] = true
+	}
+
+	for _, chaosScenario := range cm.compoundScenarios {
+		for _, part := range chaosScenario.parts {
+			involved[part.systemName] = true
+		}
+	}
+
+	systemNames := make([]string, 0, len(involved))
+	for systemName := range involved {
+		systemNames = append(systemNames, systemName)
+	}
+
+	sort.Strings(systemNames)
+
+	targets := make([]chaosTarget, 0, len(systemNames))
+
+	for _, systemName := range systemNames {
+		provider := cm.scenarioProviders[systemName]
 		systemType := cm.systemNames[systemName]
 		system := cm.systems[systemName]
 
+		if provider != nil && provider.randomized() {
+			if cm.Reporter.Seeds == nil {
+				cm.Reporter.Seeds = make(map[string]int64)
+			}
+
+			cm.Reporter.Seeds[systemName] = provider.seed
+			cm.Infof("generating random scenarios of '%s' system with seed %d", systemName, provider.seed)
+		}
+
 		killerCreator, ok := availableKillers[systemType]
 		if !ok {
 			errorMsg := "no killer registered for system '%s' of type '%s'"
 			cm.Errorf(errorMsg, systemName, systemType)
-			return errors.Errorf(errorMsg, systemName, systemType)
+			return nil, errors.Errorf(errorMsg, systemName, systemType)
 		}
 
 		killer, err := killerCreator(system)
 		if err != nil {
 			errorMsg := "failed to create killer for system '%s' of type '%s'"
 			cm.WithError(err).Errorf(errorMsg, systemName, systemType)
-			return errors.Wrapf(err, errorMsg, systemName, systemType)
+			return nil, errors.Wrapf(err, errorMsg, systemName, systemType)
 		}
 
+		targets = append(targets, chaosTarget{
+			systemName: systemName,
+			systemType: systemType,
+			system:     system,
+			provider:   provider,
+			killer:     killer,
+		})
+	}
+
+	return targets, nil
+}
+
+// runSystem executes the chaos scenarios of a single system one after the other until they're exhausted or chaos
+// execution is stopped.
+func (cm *ChaosMaker) runSystem(ctx context.Context, run *chaosRun, target chaosTarget) {
+	logger := cm.WithField("system", target.systemName)
+	logger.Infof("creating chaos in '%s' system", target.systemName)
+
+	for index := 1; !run.done(ctx); index++ {
+		scenario, ok, err := target.provider.scenario(ctx, target.system)
+		if err != nil {
+			cm.Reporter.AddMiscellaneous(audit.Message{
+				Result:  audit.FailureResult,
+				Message: errors.Wrap(err, "failed to generated scenario").Error(),
+			})
+			run.stop(err)
+
+			return
+		}
+
+		if !ok {
+			return
+		}
+
+		execution := &scenarioExecution{
+			timeout:           scenario.timeout,
+			recoveryObjective: scenario.recoveryObjective,
+			index:             index,
+			hook:              run.hook,
+			targets: []scenarioTarget{
+				{
+					chaosTarget: target,
+					identifiers: scenario.identifiers,
+				},
+			},
+		}
+
+		if err := cm.executeScenario(ctx, logger, execution); err != nil {
+			failures := run.fail(err)
+
+			if ctx.Err() == nil && cm.shouldStop(failures) {
+				logger.Warnf("stopping chaos execution after %d failed scenario(s) as per '%s' run policy",
+					failures, cm.runPolicy)
+				run.stop(nil)
+			}
+		}
+	}
+}
+
+// startCheckpoints executes checkpoint hook at checkpoint interval until returned function is called, which waits for
+// checkpoint in progress to complete. Recovery aggregates of report are computed before every checkpoint.
+func (cm *ChaosMaker) startCheckpoints(ctx context.Context, hook *Hook) func() {
+	if hook.checkpoint == nil || cm.checkpointInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticks, stopTicks := cm.checkpointTicker(cm.checkpointInterval)
+		defer stopTicks()
+
 		for {
-			scenario, ok, err := provider.scenario(system)
-			if err != nil {
-				cm.Reporter.Miscellaneous = append(
-					cm.Reporter.Miscellaneous,
-					audit.Message{
-						Result:  audit.FailureResult,
-						Message: errors.Wrap(err, "failed to generated scenario").Error(),
-					},
-				)
-				return err
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticks:
+				cm.Reporter.AggregateRecovery()
+
+				if err := hook.checkpoint(ctx); err != nil {
+					cm.WithError(err).Error("checkpoint failed")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// newTicker returns the ticks of a ticker at interval and a function which stops it.
+func newTicker(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// executeScenario kills the identifiers of all targets of scenario together and waits for all the target systems to get
+// back into desired state. If steady state is defined, it is checked before kill and after recovery so that scenario
+// isn't blamed for damage left over by previous one. Outcome of the scenario is recorded in report and error is
+// returned if scenario fails.
+func (cm *ChaosMaker) executeScenario(ctx context.Context, logger logrus.FieldLogger, execution *scenarioExecution) error {
+	if cm.steadyState != nil {
+		execution.steadyState = &audit.SteadyState{
+			Before: cm.steadyStateCheck(ctx, logger, "before scenario"),
+		}
+
+		if execution.steadyState.Before.Result != audit.SuccessResult {
+			if ctx.Err() != nil {
+				return cm.abortScenario(ctx, logger, execution, nil)
+			}
+
+			steadyStateErr := errors.Errorf("system '%s' is not in steady state before scenario",
+				joinSystemNames(execution.targets))
+
+			cm.runScenarioFailure(ctx, execution)
+			cm.addScenario(execution, nil, audit.Scenario{
+				Message: audit.Message{
+					Result:  audit.FailureResult,
+					Message: steadyStateErr.Error(),
+				},
+			})
+
+			logger.Error(steadyStateErr)
+			return steadyStateErr
+		}
+	}
+
+	if execution.hook.preScenario != nil {
+		execution.scenarioHooks().PreScenario = cm.runScenarioHook(ctx, "pre scenario", execution.hook.preScenario,
+			execution)
+	}
+
+	timing := &audit.Timing{
+		KillStart: time.Now(),
+	}
+
+	logger.Infof("creating chaos by action:\n%s", execution.identifiers())
+	err := cm.kill(ctx, execution.targets)
+	timing.KillEnd = time.Now()
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return cm.abortScenario(ctx, logger, execution, timing)
+		}
+
+		cm.runScenarioFailure(ctx, execution)
+		cm.addScenario(execution, timing, audit.Scenario{
+			Message: audit.Message{
+				Result:  audit.FailureResult,
+				Message: err.Error(),
+			},
+		})
+
+		logger.Error(err)
+		return err
+	}
+
+	var pending []scenarioTarget
+
+	// systems which wait for their recovery within Validate don't wait beyond the scenario timeout.
+	validationCtx, cancelValidation := context.WithTimeout(ctx, execution.timeout)
+	defer cancelValidation()
+
+	ok, err := wait.ExecuteWithBackoff(
+		ctx,
+		// validation is retried at short intervals so that recovery time is measured close to the actual recovery.
+		&wait.MinMaxBackoff{
+			Min: time.Second,
+			Max: 2 * time.Second,
+		},
+		func(ctx context.Context) (bool, error) {
+			validationStart := time.Now()
+			if timing.FirstValidation == nil {
+				timing.FirstValidation = &validationStart
 			}
 
+			timing.ValidationAttempts++
+
+			var err error
+
+			pending, err = validate(validationCtx, execution.targets)
+			if err != nil && validationCtx.Err() != nil && ctx.Err() == nil {
+				// systems interrupted by the scenario timeout are reported as not recovered.
+				err = nil
+			}
+
+			if err == nil && len(pending) == 0 {
+				recovered := time.Now()
+				timing.Recovered = &recovered
+				timing.RecoveryTime = recovered.Sub(timing.KillEnd)
+
+				return true, nil
+			}
+
+			return false, err
+		},
+		execution.timeout,
+	)
+
+	if ctx.Err() != nil {
+		return cm.abortScenario(ctx, logger, execution, timing)
+	}
+
+	var validationErr error
+
+	switch {
+	case err != nil:
+		validationErr = err
+	case !ok:
+		validationErr = errors.Errorf("validation failed. system '%s' didn't reach desired state",
+			joinSystemNames(pending))
+	}
+
+	if validationErr != nil {
+		logger.Error(validationErr)
+
+		// failure hook is executed before restoration so that it observes the state in which system failed.
+		cm.runScenarioFailure(ctx, execution)
+
+		scenarioReport := audit.Scenario{
+			Message: audit.Message{
+				Result:  audit.FailureResult,
+				Message: validationErr.Error(),
+			},
+		}
+
+		multiSystem := len(execution.targets) > 1
+
+		for _, target := range pending {
+			driftReporter, ok := target.system.(DriftReporter)
 			if !ok {
-				break
+				continue
 			}
 
-			cm.Infof("creating chaos by action:\n%s", scenario.identifiers)
-			if err := killer.Kill(ctx, scenario.identifiers...); err != nil {
-				errorMsg := "failed to kill identifiers for system %s of type %s"
-				cm.Reporter.Scenarios.Scenarios = append(
-					cm.Reporter.Scenarios.Scenarios,
-					audit.Scenario{
-						Identifiers: scenario.identifiers.String(),
-						Message: audit.Message{
-							Result:  audit.FailureResult,
-							Message: errors.Wrapf(err, errorMsg, systemName, systemType).Error(),
-						},
-					},
-				)
-
-				cm.WithError(err).Errorf(errorMsg, systemName, systemType)
-				return errors.Wrapf(err, errorMsg, systemName, systemType)
+			drifts := driftReporter.Drift()
+			if len(drifts) > 0 {
+				summary := make([]string, 0, len(drifts))
+				for _, drift := range drifts {
+					summary = append(summary, fmt.Sprintf("%s (%s): %s", drift.ID, drift.Type, drift.Message))
+				}
+
+				logger.Warnf("system '%s' has %d deviation(s) from desired state:\n%s", target.systemName,
+					len(drifts), strings.Join(summary, "\n"))
 			}
 
-			ok, err = wait.ExecuteWithBackoff(
-				ctx,
-				&wait.ExponentialBackoff{
-					Cap:    10 * time.Minute,
-					Factor: 2.0,
-					Jitter: 0.3,
-				},
-				system.Validate,
-				scenario.timeout,
-			)
+			for _, drift := range drifts {
+				auditDrift := audit.Drift{
+					ID:      string(drift.ID),
+					Type:    string(drift.Type),
+					Message: drift.Message,
+				}
+
+				if multiSystem {
+					auditDrift.System = target.systemName
+				}
+
+				scenarioReport.Drift = append(scenarioReport.Drift, auditDrift)
+			}
+		}
 
+		for _, target := range pending {
+			restored, err := cm.restore(ctx, logger, target.systemName, target.identifiers)
 			if err != nil {
-				errorMsg := "failed to validate system '%s'"
-				cm.Reporter.Scenarios.Scenarios = append(
-					cm.Reporter.Scenarios.Scenarios,
-					audit.Scenario{
-						Identifiers: scenario.identifiers.String(),
-						Message: audit.Message{
-							Result:  audit.FailureResult,
-							Message: errors.Wrapf(err, errorMsg, systemName).Error(),
-						},
-					},
-				)
-
-				cm.WithError(err).Errorf(errorMsg, systemName)
-				return errors.Wrapf(err, errorMsg, systemName)
+				scenarioReport.Message.Message += "; " + err.Error()
 			}
 
-			if !ok {
-				errorMsg := "validation failed. system '%s' didn't reach desired state"
-				cm.Reporter.Scenarios.Scenarios = append(
-					cm.Reporter.Scenarios.Scenarios,
-					audit.Scenario{
-						Identifiers: scenario.identifiers.String(),
-						Message: audit.Message{
-							Result:  audit.FailureResult,
-							Message: errors.Errorf(errorMsg, systemName).Error(),
-						},
-					},
-				)
-
-				cm.Errorf(errorMsg, systemName)
-				return errors.Errorf(errorMsg, systemName)
+			for _, identifier := range restored {
+				scenarioReport.Restored = append(scenarioReport.Restored, string(identifier.ID()))
+			}
+		}
+
+		cm.addScenario(execution, timing, scenarioReport)
+
+		return validationErr
+	}
+
+	if execution.steadyState != nil {
+		after := cm.steadyStateCheck(ctx, logger, "after scenario")
+		execution.steadyState.After = &after
+
+		if after.Result != audit.SuccessResult {
+			if ctx.Err() != nil {
+				return cm.abortScenario(ctx, logger, execution, timing)
 			}
 
-			cm.Reporter.Scenarios.Scenarios = append(
-				cm.Reporter.Scenarios.Scenarios,
-				audit.Scenario{
-					Identifiers: scenario.identifiers.String(),
-					Message: audit.Message{
-						Result:  audit.SuccessResult,
-						Message: "Successfully executed the scenario",
-					},
+			steadyStateErr := errors.Errorf("system '%s' recovered but is not in steady state after scenario",
+				joinSystemNames(execution.targets))
+
+			cm.runScenarioFailure(ctx, execution)
+			cm.addScenario(execution, timing, audit.Scenario{
+				Message: audit.Message{
+					Result:  audit.FailureResult,
+					Message: steadyStateErr.Error(),
 				},
-			)
+			})
+
+			logger.Error(steadyStateErr)
+			return steadyStateErr
+		}
+	}
+
+	if execution.hook.postScenario != nil {
+		execution.scenarioHooks().PostScenario = cm.runScenarioHook(ctx, "post scenario", execution.hook.postScenario,
+			execution)
+	}
 
-			cm.Infof("recovered successfully by chaos by action:\n%s", scenario.identifiers)
+	if execution.recoveryObjective > 0 && timing.RecoveryTime > execution.recoveryObjective {
+		degradedErr := errors.Errorf("system '%s' recovered in %s which exceeds recovery objective of %s",
+			joinSystemNames(execution.targets), timing.RecoveryTime, execution.recoveryObjective)
+		logger.Warn(degradedErr)
+
+		cm.addScenario(execution, timing, audit.Scenario{
+			Message: audit.Message{
+				Result:  audit.DegradedResult,
+				Message: degradedErr.Error(),
+			},
+		})
+
+		if cm.failOnDegraded {
+			return degradedErr
 		}
+
+		return nil
 	}
 
-	cm.Reporter.Miscellaneous = append(
-		cm.Reporter.Miscellaneous,
-		audit.Message{
+	cm.addScenario(execution, timing, audit.Scenario{
+		Message: audit.Message{
 			Result:  audit.SuccessResult,
-			Message: "Successfully executed all scenarios",
+			Message: "Successfully executed the scenario",
 		},
-	)
+	})
+
+	logger.Infof("recovered successfully by chaos by action:\n%s", execution.identifiers())
 
 	return nil
 }
 
-func (cm *ChaosMaker) loadSystems(ctx context.Context, hook *Hook) error {
-	if hook.preSystemLoad != nil {
-		cm.Info("pre system load hook executing")
-		result := audit.SuccessResult
-		message := "Successfully completed pre system load hook"
-
-		if err := hook.preSystemLoad(ctx); err != nil {
-			result = audit.FailureResult
-			message = errors.Wrap(err, "pre system load hook failed").Error()
-			cm.WithError(err).Warn("pre system load hook failed")
+// kill kills the identifiers of all targets concurrently and returns the error of first target which failed.
+func (cm *ChaosMaker) kill(ctx context.Context, targets []scenarioTarget) error {
+	errs := make([]error, len(targets))
+
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+
+		go func(i int, target scenarioTarget) {
+			defer wg.Done()
+
+			if err := target.killer.Kill(ctx, target.identifiers...); err != nil {
+				errs[i] = errors.Wrapf(err, "failed to kill identifiers for system %s of type %s",
+					target.systemName, target.systemType)
+			}
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// validate validates all target systems concurrently and returns the targets whose systems are not in desired state.
+func validate(ctx context.Context, targets []scenarioTarget) ([]scenarioTarget, error) {
+	recovered := make([]bool, len(targets))
+	errs := make([]error, len(targets))
 
-		cm.Reporter.Load.PreLoad = audit.Message{
-			Result:  result,
-			Message: message,
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+
+		go func(i int, target scenarioTarget) {
+			defer wg.Done()
+
+			ok, err := target.system.Validate(ctx)
+			if err != nil {
+				errs[i] = errors.Wrapf(err, "failed to validate system '%s'", target.systemName)
+			}
+
+			recovered[i] = err == nil && ok
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	var pending []scenarioTarget
+
+	for i, target := range targets {
+		if !recovered[i] {
+			pending = append(pending, target)
 		}
 	}
 
+	for _, err := range errs {
+		if err != nil {
+			return pending, err
+		}
+	}
+
+	return pending, nil
+}
+
+// addScenario adds the report information of scenario executed with given timing. timing is nil if scenario ended
+// before kill.
+func (cm *ChaosMaker) addScenario(execution *scenarioExecution, timing *audit.Timing, scenario audit.Scenario) {
+	scenario.System = joinSystemNames(execution.targets)
+	scenario.Compound = len(execution.targets) > 1
+	scenario.Identifiers = execution.identifiers()
+	scenario.SteadyState = execution.steadyState
+	scenario.Hooks = execution.hooks
+	scenario.Timing = timing
+
+	if timing != nil {
+		scenario.Duration = time.Since(timing.KillStart)
+	}
+
+	cm.Reporter.AddScenario(scenario)
+}
+
+// restore restores the resources lost in failed scenario if system supports restoration.
+func (cm *ChaosMaker) restore(
+	ctx context.Context,
+	logger logrus.FieldLogger,
+	systemName string,
+	identifiers Identifiers,
+) (Identifiers, error) {
+	restorer, ok := cm.systems[systemName].(Restorer)
+	if !ok {
+		return nil, nil
+	}
+
+	restored, err := restorer.Restore(ctx, identifiers...)
+	if err != nil {
+		errorMsg := "failed to restore system '%s'"
+		logger.WithError(err).Errorf(errorMsg, systemName)
+		return restored, errors.Wrapf(err, errorMsg, systemName)
+	}
+
+	if len(restored) > 0 {
+		logger.Warnf("restored resources of system '%s' which didn't recover:\n%s", systemName, restored)
+	}
+
+	return restored, nil
+}
+
+func (cm *ChaosMaker) loadSystems(ctx context.Context, hook *Hook) error {
+	if hook.preSystemLoad != nil {
+		cm.Reporter.Load.PreLoad = cm.runHook(ctx, "pre system load", hook.preSystemLoad)
+	}
+
 	if hook.postSystemLoad != nil {
 		defer func() {
-			cm.Info("post system load hook executing")
-			result := audit.SuccessResult
-			message := "Successfully completed post system load hook"
-
-			if err := hook.postSystemLoad(ctx); err != nil {
-				result = audit.FailureResult
-				message = errors.Wrap(err, "post system load hook failed").Error()
-				cm.WithError(err).Warn("post system load hook failed")
-			}
-
-			cm.Reporter.Load.PreLoad = audit.Message{
-				Result:  result,
-				Message: message,
-			}
+			cm.Reporter.Load.PostLoad = cm.runHook(ctx, "post system load", hook.postSystemLoad)
 		}()
 	}
 
 	cm.Info("system(s) are being loaded")
 
+	start := time.Now()
+
 	for name, system := range cm.systems {
 		if err := system.Load(ctx); err != nil {
 			errorMsg := "system '%s' failed to load"
 			cm.Load.Message = audit.Message{
-				Result:  audit.FailureResult,
-				Message: errors.Wrap(err, errorMsg).Error(),
+				Result:   audit.FailureResult,
+				Message:  errors.Wrap(err, errorMsg).Error(),
+				Duration: time.Since(start),
 			}
 
 			cm.WithError(err).Errorf(errorMsg, name)
@@ -268,8 +826,9 @@ func (cm *ChaosMaker) loadSystems(ctx context.Context, hook *Hook) error {
 	}
 
 	cm.Reporter.Load.Message = audit.Message{
-		Result:  audit.SuccessResult,
-		Message: "system(s) are loaded successfully",
+		Result:   audit.SuccessResult,
+		Message:  "system(s) are loaded successfully",
+		Duration: time.Since(start),
 	}
 
 	cm.Info("system(s) are loaded")
@@ -277,61 +836,172 @@ func (cm *ChaosMaker) loadSystems(ctx context.Context, hook *Hook) error {
 	return nil
 }
 
-func (cm *ChaosMaker) readyCheck(ctx context.Context, hook *Hook) error {
-	if hook.preReady != nil {
-		cm.Info("pre ready hook executing")
-		result := audit.SuccessResult
-		message := "Successfully completed pre ready hook"
-
-		if err := hook.preReady(ctx); err != nil {
-			result = audit.FailureResult
-			message = errors.Wrap(err, "pre ready hook failed").Error()
-			cm.WithError(err).Warn("pre ready hook failed")
+// runHook executes the user defined hook and returns its report information.
+func (cm *ChaosMaker) runHook(ctx context.Context, name string, hook func(context.Context) error) audit.Message {
+	cm.Infof("%s hook executing", name)
+
+	hookCtx, out := withOutput(ctx)
+
+	start := time.Now()
+	message := audit.Message{
+		Result:  audit.SuccessResult,
+		Message: fmt.Sprintf("Successfully completed %s hook", name),
+	}
+
+	if err := hook(hookCtx); err != nil {
+		message.Result = audit.FailureResult
+		message.Message = errors.Wrapf(err, "%s hook failed", name).Error()
+		cm.WithError(err).Warnf("%s hook failed", name)
+	}
+
+	message.Duration = time.Since(start)
+	message.Output = out.String()
+
+	return message
+}
+
+// runScenarioHook executes the scenario hook with the description of scenario in context and returns its report
+// information.
+func (cm *ChaosMaker) runScenarioHook(
+	ctx context.Context,
+	name string,
+	hook func(context.Context) error,
+	execution *scenarioExecution,
+) *audit.Message {
+	message := cm.runHook(ContextWithScenario(ctx, execution.info()), name, hook)
+	return &message
+}
+
+// runScenarioFailure executes the scenario failure hook, if it is defined, for scenario which failed.
+func (cm *ChaosMaker) runScenarioFailure(ctx context.Context, execution *scenarioExecution) {
+	if execution.hook.scenarioFailure == nil {
+		return
+	}
+
+	execution.scenarioHooks().ScenarioFailure = cm.runScenarioHook(ctx, "scenario failure",
+		execution.hook.scenarioFailure, execution)
+}
+
+// abortScenario records the scenario interrupted by cancellation of context as aborted, since it is unknown whether the
+// systems would have recovered.
+func (cm *ChaosMaker) abortScenario(
+	ctx context.Context,
+	logger logrus.FieldLogger,
+	execution *scenarioExecution,
+	timing *audit.Timing,
+) error {
+	abortErr := errors.Wrapf(ctx.Err(), "scenario of system '%s' aborted", joinSystemNames(execution.targets))
+
+	cm.addScenario(execution, timing, audit.Scenario{
+		Message: audit.Message{
+			Result:  audit.AbortedResult,
+			Message: abortErr.Error(),
+		},
+	})
+
+	logger.Warn(abortErr)
+
+	return abortErr
+}
+
+// steadyStateCheck waits for steady state of systems within its timeout and returns the report information of check.
+func (cm *ChaosMaker) steadyStateCheck(ctx context.Context, logger logrus.FieldLogger, phase string) audit.Message {
+	logger.Infof("checking steady state %s", phase)
+
+	start := time.Now()
+
+	ok, out, err := checkReady(ctx, cm.steadyState, cm.steadyStateTimeout)
+
+	switch {
+	case err != nil:
+		errorMsg := fmt.Sprintf("failed to check steady state %s", phase)
+		logger.WithError(err).Error(errorMsg)
+
+		return audit.Message{
+			Result:   audit.FailureResult,
+			Message:  errors.Wrap(err, errorMsg).Error(),
+			Duration: time.Since(start),
+			Output:   out,
 		}
+	case !ok:
+		errorMsg := fmt.Sprintf("system(s) didn't reach steady state %s", phase)
+		logger.Error(errorMsg)
 
-		cm.Reporter.Ready.PreReady = audit.Message{
-			Result:  result,
-			Message: message,
+		return audit.Message{
+			Result:   audit.FailureResult,
+			Message:  errorMsg,
+			Duration: time.Since(start),
+			Output:   out,
 		}
 	}
 
-	if hook.postReady != nil {
-		defer func() {
-			cm.Info("post ready hook executing")
-			result := audit.SuccessResult
-			message := "Successfully completed post ready hook"
-
-			if err := hook.postReady(ctx); err != nil {
-				result = audit.FailureResult
-				message = errors.Wrap(err, "post ready hook failed").Error()
-				cm.WithError(err).Warn("post ready hook failed")
-			}
-
-			cm.Reporter.Ready.PostReady = audit.Message{
-				Result:  result,
-				Message: message,
-			}
-		}()
+	return audit.Message{
+		Result:   audit.SuccessResult,
+		Message:  fmt.Sprintf("system(s) are in steady state %s", phase),
+		Duration: time.Since(start),
+		Output:   out,
 	}
+}
 
-	cm.Info("initiating readiness check")
+// checkReady waits for readyCond to be ready within timeout. Output recorded by the last evaluation of readyCond is
+// returned along with the outcome.
+func checkReady(ctx context.Context, readyCond ReadyCond, timeout time.Duration) (bool, string, error) {
+	readyCtx, out := withOutput(ctx)
 
 	ok, err := wait.ExecuteWithBackoff(
-		ctx,
+		readyCtx,
 		&wait.ExponentialBackoff{
 			Duration: 1 * time.Second,
 			Cap:      10 * time.Minute,
 			Factor:   1.5,
 			Jitter:   0.7,
 		},
-		cm.ready.Ready,
-		cm.readyTimeout,
+		func(ctx context.Context) (bool, error) {
+			out.reset()
+			return readyCond.Ready(ctx)
+		},
+		timeout,
 	)
+
+	return ok, out.String(), err
+}
+
+func (cm *ChaosMaker) readyCheck(ctx context.Context, hook *Hook) error {
+	if hook.preReady != nil {
+		cm.Reporter.Ready.PreReady = cm.runHook(ctx, "pre ready", hook.preReady)
+	}
+
+	if hook.postReady != nil {
+		defer func() {
+			cm.Reporter.Ready.PostReady = cm.runHook(ctx, "post ready", hook.postReady)
+		}()
+	}
+
+	cm.Info("initiating readiness check")
+
+	start := time.Now()
+
+	ok, out, err := checkReady(ctx, cm.ready, cm.readyTimeout)
+	if ctx.Err() == context.Canceled {
+		abortErr := errors.Wrap(ctx.Err(), "ready phase aborted")
+		cm.Reporter.Ready.Message = audit.Message{
+			Result:   audit.AbortedResult,
+			Message:  abortErr.Error(),
+			Duration: time.Since(start),
+			Output:   out,
+		}
+
+		cm.Warn(abortErr)
+		return abortErr
+	}
+
 	if err != nil {
 		errorMsg := "system(s) failed to reach ready state"
 		cm.Reporter.Ready.Message = audit.Message{
-			Result:  audit.FailureResult,
-			Message: errors.Wrap(err, errorMsg).Error(),
+			Result:   audit.FailureResult,
+			Message:  errors.Wrap(err, errorMsg).Error(),
+			Duration: time.Since(start),
+			Output:   out,
 		}
 
 		cm.WithError(err).Error(errorMsg)
@@ -341,8 +1011,10 @@ func (cm *ChaosMaker) readyCheck(ctx context.Context, hook *Hook) error {
 	if !ok {
 		errorMsg := "system(s) didn't reach ready state"
 		cm.Reporter.Ready.Message = audit.Message{
-			Result:  audit.FailureResult,
-			Message: errorMsg,
+			Result:   audit.FailureResult,
+			Message:  errorMsg,
+			Duration: time.Since(start),
+			Output:   out,
 		}
 
 		cm.Errorf(errorMsg)
@@ -350,8 +1022,10 @@ func (cm *ChaosMaker) readyCheck(ctx context.Context, hook *Hook) error {
 	}
 
 	cm.Reporter.Ready.Message = audit.Message{
-		Result:  audit.SuccessResult,
-		Message: "Successfully completed ready phase",
+		Result:   audit.SuccessResult,
+		Message:  "Successfully completed ready phase",
+		Duration: time.Since(start),
+		Output:   out,
 	}
 
 	cm.Info("system(s) are ready for chaos testing")
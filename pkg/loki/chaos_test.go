@@ -16,13 +16,17 @@ package loki
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+
+	"github.com/narahari92/loki/pkg/audit"
 )
 
 func TestChaos(t *testing.T) {
@@ -84,3 +88,717 @@ func TestChaos(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestChaosRunPolicy(t *testing.T) {
+	registerChaosTestSystem("failing-test-system", nil, func(System) Killer {
+		return KillerFunc(func(context.Context, ...Identifier) error {
+			return errors.New("kill failed")
+		})
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: failing-test-system
+  name: failing
+  resources:
+  - resource1
+  - resource2
+destroy:
+  scenarios:
+  - system: failing
+    resources:
+    - resource1
+  - system: failing
+    resources:
+    - resource2
+  - system: failing
+    resources:
+    - resource1
+    - resource2
+`
+	tests := []struct {
+		description string
+		policy      RunPolicy
+		maxFailures int
+		failures    int
+	}{
+		{
+			description: "fail fast",
+			policy:      FailFast,
+			failures:    1,
+		},
+		{
+			description: "run all",
+			policy:      RunAll,
+			failures:    3,
+		},
+		{
+			description: "stop after failures",
+			policy:      StopAfterFailures,
+			maxFailures: 2,
+			failures:    2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			chaosMaker := newChaosMaker(t, conf)
+
+			err := chaosMaker.SetRunPolicy(test.policy, test.maxFailures)
+			require.NoError(t, err)
+
+			err = chaosMaker.CreateChaos(context.Background())
+			require.Error(t, err)
+
+			failures, ok := err.(ScenarioFailures)
+			require.Equal(t, true, ok)
+			require.Equal(t, test.failures, len(failures))
+			require.Equal(t, test.failures, len(chaosMaker.Reporter.Scenarios.Scenarios))
+		})
+	}
+}
+
+type driftingSystem struct {
+	*TestSystem
+}
+
+func (d *driftingSystem) Drift() []Drift {
+	var drift []Drift
+
+	for resource := range d.Resources {
+		if _, ok := d.State[resource]; !ok {
+			drift = append(drift, Drift{ID: resource.ID(), Type: MissingDrift, Message: "resource is missing"})
+		}
+	}
+
+	return drift
+}
+
+func TestChaosDrift(t *testing.T) {
+	registerChaosTestSystem("drifting-test-system", func(system *TestSystem) System {
+		return &driftingSystem{TestSystem: system}
+	}, func(system System) Killer {
+		return &TestKiller{
+			System: system.(*driftingSystem).TestSystem,
+		}
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: drifting-test-system
+  name: drifting
+  resources:
+  - resource1
+  - resource2
+destroy:
+  scenarios:
+  - system: drifting
+    timeout: 1ms
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, len(chaosMaker.Reporter.Scenarios.Scenarios))
+	require.Equal(t, []audit.Drift{
+		{
+			ID:      "resource1",
+			Type:    "missing",
+			Message: "resource is missing",
+		},
+	}, chaosMaker.Reporter.Scenarios.Scenarios[0].Drift)
+
+	timing := chaosMaker.Reporter.Scenarios.Scenarios[0].Timing
+	require.NotNil(t, timing)
+	require.NotNil(t, timing.FirstValidation)
+	require.Nil(t, timing.Recovered)
+	require.LessOrEqual(t, 1, timing.ValidationAttempts)
+	require.Equal(t, 0, len(chaosMaker.Reporter.Recovery))
+}
+
+func TestChaosAbort(t *testing.T) {
+	registerChaosTestSystem("aborted-test-system", func(system *TestSystem) System {
+		return &driftingSystem{TestSystem: system}
+	}, func(system System) Killer {
+		return &TestKiller{
+			System: system.(*driftingSystem).TestSystem,
+		}
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: aborted-test-system
+  name: aborted
+  resources:
+  - resource1
+  - resource2
+destroy:
+  scenarios:
+  - system: aborted
+    timeout: 1h
+    resources:
+    - resource1
+  - system: aborted
+    resources:
+    - resource2
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.SetRunPolicy(RunAll, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	err = chaosMaker.CreateChaos(ctx)
+	require.Error(t, err)
+	require.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	require.Equal(t, 1, len(chaosMaker.Reporter.Scenarios.Scenarios))
+	require.Equal(t, audit.AbortedResult, chaosMaker.Reporter.Scenarios.Scenarios[0].Result)
+
+	miscellaneous := chaosMaker.Reporter.Miscellaneous
+	require.Equal(t, audit.AbortedResult, miscellaneous[len(miscellaneous)-1].Result)
+}
+
+func TestChaosReadyAbort(t *testing.T) {
+	registerChaosTestSystem("ready-abort-test-system", nil, nil)
+
+	conf := `
+ready:
+  after: 1h
+systems:
+- type: ready-abort-test-system
+  name: aborted
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: aborted
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	err := chaosMaker.CreateChaos(ctx)
+	require.Error(t, err)
+	require.Equal(t, context.Canceled, errors.Cause(err))
+	require.Equal(t, audit.AbortedResult, chaosMaker.Reporter.Ready.Result)
+	require.Equal(t, 0, len(chaosMaker.Reporter.Scenarios.Scenarios))
+}
+
+func TestChaosRecoveryTiming(t *testing.T) {
+	registerChaosTestSystem("recovering-test-system", nil, nil)
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: recovering-test-system
+  name: recovering
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: recovering
+    resources:
+    - resource1
+  - system: recovering
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.NoError(t, err)
+
+	for _, scenario := range chaosMaker.Reporter.Scenarios.Scenarios {
+		require.NotNil(t, scenario.Timing)
+		require.NotNil(t, scenario.Timing.Recovered)
+		require.Equal(t, 1, scenario.Timing.ValidationAttempts)
+		require.Equal(t, scenario.Timing.Recovered.Sub(scenario.Timing.KillEnd), scenario.Timing.RecoveryTime)
+	}
+
+	recovery, ok := chaosMaker.Reporter.Recovery["recovering"]
+	require.Equal(t, true, ok)
+	require.Equal(t, 2, recovery.Scenarios)
+}
+
+type slowSystem struct {
+	*TestSystem
+}
+
+func (s *slowSystem) Validate(ctx context.Context) (bool, error) {
+	time.Sleep(20 * time.Millisecond)
+
+	return s.TestSystem.Validate(ctx)
+}
+
+func TestChaosDegraded(t *testing.T) {
+	registerChaosTestSystem("slow-test-system", func(system *TestSystem) System {
+		return &slowSystem{TestSystem: system}
+	}, nil)
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: slow-test-system
+  name: slow
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: slow
+    recoveryObjective: 1ms
+    resources:
+    - resource1
+  - system: slow
+    resources:
+    - resource1
+`
+	tests := []struct {
+		description    string
+		failOnDegraded bool
+	}{
+		{
+			description: "degraded scenarios pass",
+		},
+		{
+			description:    "degraded scenarios fail",
+			failOnDegraded: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			chaosMaker := newChaosMaker(t, conf)
+			chaosMaker.SetFailOnDegraded(test.failOnDegraded)
+
+			err := chaosMaker.CreateChaos(context.Background())
+			scenarios := chaosMaker.Reporter.Scenarios.Scenarios
+			require.Equal(t, audit.DegradedResult, scenarios[0].Result)
+
+			if test.failOnDegraded {
+				require.Error(t, err)
+				require.Equal(t, 1, len(scenarios))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 2, len(scenarios))
+			require.Equal(t, audit.SuccessResult, scenarios[1].Result)
+		})
+	}
+}
+
+// flakySystem fails the first validation after every kill.
+type flakySystem struct {
+	*TestSystem
+	validated bool
+}
+
+func (f *flakySystem) Validate(ctx context.Context) (bool, error) {
+	if !f.validated {
+		f.validated = true
+		return false, nil
+	}
+
+	return f.TestSystem.Validate(ctx)
+}
+
+func TestChaosWithinRecoveryObjective(t *testing.T) {
+	registerChaosTestSystem("flaky-test-system", func(system *TestSystem) System {
+		return &flakySystem{TestSystem: system}
+	}, func(system System) Killer {
+		return KillerFunc(func(context.Context, ...Identifier) error {
+			system.(*flakySystem).validated = false
+			return nil
+		})
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: flaky-test-system
+  name: flaky
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: flaky
+    recoveryObjective: 5s
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+	chaosMaker.SetFailOnDegraded(true)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.NoError(t, err)
+
+	// recovery after a failed validation is measured within the retry interval, not an exponential backoff.
+	scenarios := chaosMaker.Reporter.Scenarios.Scenarios
+	require.Equal(t, 1, len(scenarios))
+	require.Equal(t, audit.SuccessResult, scenarios[0].Result)
+	require.Equal(t, 2, scenarios[0].Timing.ValidationAttempts)
+	require.Less(t, int64(scenarios[0].Timing.RecoveryTime), int64(5*time.Second))
+}
+
+func TestChaosConcurrency(t *testing.T) {
+	registerChaosTestSystem("concurrent-test-system", func(system *TestSystem) System {
+		return &slowSystem{TestSystem: system}
+	}, nil)
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: concurrent-test-system
+  name: system1
+  resources:
+  - resource1
+- type: concurrent-test-system
+  name: system2
+  resources:
+  - resource1
+run:
+  concurrency: 2
+destroy:
+  scenarios:
+  - system: system1
+    resources:
+    - resource1
+  - system: system1
+    resources:
+    - resource1
+  - system: system2
+    resources:
+    - resource1
+  - system: system2
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.NoError(t, err)
+
+	scenarios := make(map[string]int)
+	firstTimings := make(map[string]*audit.Timing)
+
+	for _, scenario := range chaosMaker.Reporter.Scenarios.Scenarios {
+		require.Equal(t, audit.SuccessResult, scenario.Result)
+		scenarios[scenario.System]++
+
+		if _, ok := firstTimings[scenario.System]; !ok {
+			firstTimings[scenario.System] = scenario.Timing
+		}
+	}
+
+	require.Equal(t, map[string]int{"system1": 2, "system2": 2}, scenarios)
+
+	// first scenarios of both systems overlap as they're executed concurrently.
+	system1Timing, system2Timing := firstTimings["system1"], firstTimings["system2"]
+	require.Equal(t, true, system1Timing.KillStart.Before(*system2Timing.Recovered))
+	require.Equal(t, true, system2Timing.KillStart.Before(*system1Timing.Recovered))
+}
+
+func TestChaosCompound(t *testing.T) {
+	registerChaosTestSystem("compound-test-system", nil, nil)
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: compound-test-system
+  name: system1
+  resources:
+  - resource1
+- type: compound-test-system
+  name: system2
+  resources:
+  - resource2
+destroy:
+  scenarios:
+  - compound:
+    - system: system1
+      resources:
+      - resource1
+    - system: system2
+      resources:
+      - resource2
+    timeout: 1m
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(chaosMaker.Reporter.Scenarios.Scenarios))
+
+	scenario := chaosMaker.Reporter.Scenarios.Scenarios[0]
+	require.Equal(t, audit.SuccessResult, scenario.Result)
+	require.Equal(t, "system1,system2", scenario.System)
+	require.Equal(t, true, scenario.Compound)
+	require.Contains(t, scenario.Identifiers, "system 'system1'")
+	require.Contains(t, scenario.Identifiers, "system 'system2'")
+
+	// compound recovery isn't mixed with recovery of scenarios of individual systems.
+	require.Nil(t, chaosMaker.Reporter.Recovery)
+	require.Equal(t, 1, chaosMaker.Reporter.CompoundRecovery["system1,system2"].Scenarios)
+}
+
+func TestChaosSteadyState(t *testing.T) {
+	var checks int32
+
+	RegisterReadyParser("testSteadyState", func(*Config) ReadyParser {
+		return ReadyParserFunc(func(map[string]interface{}) (ReadyCond, error) {
+			return ReadyFunc(func(context.Context) (bool, error) {
+				// steady state holds for checks before and after first scenario only.
+				return atomic.AddInt32(&checks, 1) <= 2, nil
+			}), nil
+		})
+	})
+	registerChaosTestSystem("steady-test-system", nil, nil)
+
+	conf := `
+ready:
+  after: 0s
+steadyState:
+  testSteadyState: true
+  timeout: 1s
+systems:
+- type: steady-test-system
+  name: steady
+  resources:
+  - resource1
+run:
+  policy: runAll
+destroy:
+  scenarios:
+  - system: steady
+    resources:
+    - resource1
+  - system: steady
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	err := chaosMaker.CreateChaos(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 2, len(chaosMaker.Reporter.Scenarios.Scenarios))
+
+	healthy := chaosMaker.Reporter.Scenarios.Scenarios[0]
+	require.Equal(t, audit.SuccessResult, healthy.Result)
+	require.NotNil(t, healthy.SteadyState)
+	require.Equal(t, audit.SuccessResult, healthy.SteadyState.Before.Result)
+	require.NotNil(t, healthy.SteadyState.After)
+	require.Equal(t, audit.SuccessResult, healthy.SteadyState.After.Result)
+
+	unhealthy := chaosMaker.Reporter.Scenarios.Scenarios[1]
+	require.Equal(t, audit.FailureResult, unhealthy.Result)
+	require.Equal(t, audit.FailureResult, unhealthy.SteadyState.Before.Result)
+	require.Nil(t, unhealthy.SteadyState.After)
+	require.Nil(t, unhealthy.Timing)
+}
+
+func TestChaosScenarioHooks(t *testing.T) {
+	registerChaosTestSystem("scenario-hooks-test-system", nil, func(System) Killer {
+		return KillerFunc(func(_ context.Context, identifiers ...Identifier) error {
+			for _, identifier := range identifiers {
+				if identifier.ID() == "resource2" {
+					return errors.New("resource2 can't be killed")
+				}
+			}
+
+			return nil
+		})
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: scenario-hooks-test-system
+  name: hooked
+  resources:
+  - resource1
+  - resource2
+run:
+  policy: runAll
+destroy:
+  scenarios:
+  - system: hooked
+    resources:
+    - resource1
+  - system: hooked
+    resources:
+    - resource2
+`
+	chaosMaker := newChaosMaker(t, conf)
+
+	var executed []string
+
+	record := func(phase string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			info, ok := ScenarioFromContext(ctx)
+			require.Equal(t, true, ok)
+			require.Equal(t, "hooked", info.System)
+			require.Equal(t, 1, len(info.Identifiers))
+
+			executed = append(executed, fmt.Sprintf("%s %d %s", phase, info.Index, info.Identifiers[0].ID()))
+
+			return nil
+		}
+	}
+
+	err := chaosMaker.CreateChaos(
+		context.Background(),
+		WithPreScenario(record("pre")),
+		WithPostScenario(record("post")),
+		WithScenarioFailure(record("failure")),
+	)
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"pre 1 resource1",
+		"post 1 resource1",
+		"pre 2 resource2",
+		"failure 2 resource2",
+	}, executed)
+
+	scenarios := chaosMaker.Reporter.Scenarios.Scenarios
+	require.Equal(t, 2, len(scenarios))
+
+	require.NotNil(t, scenarios[0].Hooks)
+	require.Equal(t, audit.SuccessResult, scenarios[0].Hooks.PreScenario.Result)
+	require.Equal(t, audit.SuccessResult, scenarios[0].Hooks.PostScenario.Result)
+	require.Nil(t, scenarios[0].Hooks.ScenarioFailure)
+
+	require.NotNil(t, scenarios[1].Hooks)
+	require.Equal(t, audit.SuccessResult, scenarios[1].Hooks.PreScenario.Result)
+	require.Nil(t, scenarios[1].Hooks.PostScenario)
+	require.Equal(t, audit.SuccessResult, scenarios[1].Hooks.ScenarioFailure.Result)
+}
+
+func TestChaosCheckpoint(t *testing.T) {
+	ticks := make(chan time.Time)
+	checkpointed := make(chan int, 1)
+
+	// every kill ticks checkpoint and waits for it to complete, so checkpoints interleave with scenarios.
+	registerChaosTestSystem("checkpoint-test-system", nil, func(System) Killer {
+		return KillerFunc(func(context.Context, ...Identifier) error {
+			ticks <- time.Now()
+			<-checkpointed
+
+			return nil
+		})
+	})
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: checkpoint-test-system
+  name: checkpointed
+  resources:
+  - resource1
+run:
+  checkpointInterval: 100ms
+destroy:
+  scenarios:
+  - system: checkpointed
+    resources:
+    - resource1
+  - system: checkpointed
+    resources:
+    - resource1
+  - system: checkpointed
+    resources:
+    - resource1
+`
+	chaosMaker := newChaosMaker(t, conf)
+	require.Equal(t, 100*time.Millisecond, chaosMaker.checkpointInterval)
+
+	var interval time.Duration
+
+	stopped := false
+	chaosMaker.checkpointTicker = func(checkpointInterval time.Duration) (<-chan time.Time, func()) {
+		interval = checkpointInterval
+		return ticks, func() { stopped = true }
+	}
+
+	// recovery of scenarios executed so far is aggregated before every checkpoint.
+	var recovered []int
+
+	err := chaosMaker.CreateChaos(context.Background(), WithCheckpoint(func(context.Context) error {
+		recovered = append(recovered, chaosMaker.Reporter.Recovery["checkpointed"].Scenarios)
+		checkpointed <- len(recovered)
+
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, interval)
+	require.Equal(t, []int{0, 1, 2}, recovered)
+	require.Equal(t, true, stopped)
+
+	// checkpoints don't run once chaos execution is done.
+	select {
+	case ticks <- time.Now():
+		require.Fail(t, "checkpoint ticked after chaos execution")
+	default:
+	}
+}
+
+// registerChaosTestSystem registers systemType whose systems are TestSystem, or wrap one if wrap is defined, and whose
+// killer is created by killer, or doesn't kill anything if killer isn't defined.
+func registerChaosTestSystem(systemType string, wrap func(*TestSystem) System, killer func(System) Killer) {
+	RegisterSystem(systemType, func() System {
+		system := &TestSystem{
+			Resources: make(map[TestIdentifier]bool),
+			State:     make(map[TestIdentifier]bool),
+		}
+
+		if wrap == nil {
+			return system
+		}
+
+		return wrap(system)
+	})
+	RegisterDestroyer(systemType, DestroyerTest())
+	RegisterKiller(systemType, func(system System) (Killer, error) {
+		if killer == nil {
+			return KillerFunc(func(context.Context, ...Identifier) error {
+				return nil
+			}), nil
+		}
+
+		return killer(system), nil
+	})
+}
+
+// newChaosMaker parses conf and returns ChaosMaker which executes it.
+func newChaosMaker(t *testing.T, conf string) *ChaosMaker {
+	configuration := NewConfig()
+
+	err := configuration.Parse([]byte(conf))
+	require.NoError(t, err)
+
+	return &ChaosMaker{
+		Config:      configuration,
+		FieldLogger: logrus.New(),
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	combinationsKey         = "combinations"
+	maxScenariosKey         = "maxScenarios"
+	singleCombinations      = "single"
+	allCombinations         = "all"
+	pairwiseCombinations    = "pairwise"
+	defaultCombinationsSize = 2
+)
+
+// combinations defines scenarios which are systematically generated from the identifiers of system instead of randomly.
+type combinations struct {
+	// mode is one of single, which kills each resource once, all, which kills every combination of resources up to
+	// maxResources, or pairwise, which kills every pair of resources together in at least one scenario.
+	mode string
+	// maxResources is the maximum number of resources killed by a scenario of all or pairwise mode.
+	maxResources int
+	// maxScenarios fails generation when more scenarios would be generated, unlimited if 0.
+	maxScenarios      int
+	timeout           time.Duration
+	recoveryObjective time.Duration
+}
+
+func parseCombinations(scenario map[string]interface{}) (*combinations, error) {
+	combinationsConf, ok := scenario[combinationsKey].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("'%s' field should be of type map", combinationsKey)
+	}
+
+	modeValue, ok := combinationsConf[modeKey]
+	if !ok {
+		return nil, errors.Errorf("'%s' field is mandatory in '%s' section", modeKey, combinationsKey)
+	}
+
+	mode, ok := modeValue.(string)
+	if !ok {
+		return nil, errors.Errorf(strTypeErrMsg, modeKey)
+	}
+
+	if mode != singleCombinations && mode != allCombinations && mode != pairwiseCombinations {
+		return nil, errors.Errorf("unsupported %s '%s', should be one of '%s', '%s' or '%s'", modeKey, mode,
+			singleCombinations, allCombinations, pairwiseCombinations)
+	}
+
+	timeout, recoveryObjective, err := parseTimeouts(scenario)
+	if err != nil {
+		return nil, err
+	}
+
+	chaosCombinations := &combinations{
+		mode:              mode,
+		maxResources:      defaultCombinationsSize,
+		timeout:           timeout,
+		recoveryObjective: recoveryObjective,
+	}
+
+	if mode == singleCombinations {
+		chaosCombinations.maxResources = 1
+	}
+
+	if maxValue, ok := combinationsConf[maxResourcesKey]; ok {
+		if mode == singleCombinations {
+			return nil, errors.Errorf("'%s' field is not supported in '%s' mode", maxResourcesKey, singleCombinations)
+		}
+
+		maxResources, ok := maxValue.(float64)
+		if !ok {
+			return nil, errors.Errorf("'%s' field should be of type int", maxResourcesKey)
+		}
+
+		chaosCombinations.maxResources = int(maxResources)
+	}
+
+	if mode == allCombinations && chaosCombinations.maxResources < 1 {
+		return nil, errors.Errorf("'%s' should be at least 1", maxResourcesKey)
+	}
+
+	if mode == pairwiseCombinations && chaosCombinations.maxResources < 2 {
+		return nil, errors.Errorf("'%s' should be at least 2 for '%s' mode", maxResourcesKey, pairwiseCombinations)
+	}
+
+	if maxScenariosValue, ok := combinationsConf[maxScenariosKey]; ok {
+		maxScenarios, ok := maxScenariosValue.(float64)
+		if !ok {
+			return nil, errors.Errorf("'%s' field should be of type int", maxScenariosKey)
+		}
+
+		if maxScenarios < 1 {
+			return nil, errors.Errorf("'%s' should be at least 1", maxScenariosKey)
+		}
+
+		chaosCombinations.maxScenarios = int(maxScenarios)
+	}
+
+	return chaosCombinations, nil
+}
+
+// generate generates the scenarios of combinations from given identifiers, leaving out the ones rejected by excluded.
+// Identifiers are expected to be sorted so that same scenarios are generated in same order.
+func (c *combinations) generate(identifiers Identifiers, excluded func(Identifiers) bool) ([]Identifiers, error) {
+	var generated []Identifiers
+
+	selected := func(indices []int) Identifiers {
+		selection := make(Identifiers, 0, len(indices))
+		for _, index := range indices {
+			selection = append(selection, identifiers[index])
+		}
+
+		return selection
+	}
+
+	excludedIndices := func(indices []int) bool {
+		return excluded(selected(indices))
+	}
+
+	add := func(indices []int) error {
+		if excludedIndices(indices) {
+			return nil
+		}
+
+		if c.maxScenarios > 0 && len(generated) == c.maxScenarios {
+			return errors.Errorf("'%s' combinations generate more than %d scenarios defined by '%s'", c.mode,
+				c.maxScenarios, maxScenariosKey)
+		}
+
+		generated = append(generated, selected(indices))
+
+		return nil
+	}
+
+	switch c.mode {
+	case pairwiseCombinations:
+		if len(identifiers) < 2 {
+			return nil, errors.Errorf("'%s' combinations need at least 2 killable resources in system", c.mode)
+		}
+
+		for _, indices := range pairwise(len(identifiers), c.maxResources, excludedIndices) {
+			if err := add(indices); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		for size := 1; size <= c.maxResources && size <= len(identifiers); size++ {
+			if err := forEachCombination(len(identifiers), size, add); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return generated, nil
+}
+
+// forEachCombination calls fn with the indices of every combination of size out of n elements in lexicographic order,
+// until fn returns an error.
+func forEachCombination(n, size int, fn func([]int) error) error {
+	indices := make([]int, size)
+	for i := range indices {
+		indices[i] = i
+	}
+
+	for {
+		combination := make([]int, size)
+		copy(combination, indices)
+
+		if err := fn(combination); err != nil {
+			return err
+		}
+
+		// advances the rightmost index which hasn't reached its last position and resets the ones after it.
+		i := size - 1
+		for i >= 0 && indices[i] == n-size+i {
+			i--
+		}
+
+		if i < 0 {
+			return nil
+		}
+
+		indices[i]++
+
+		for j := i + 1; j < size; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+}
+
+// pairwise greedily computes groups of at most size out of n elements such that every pair of elements, except the ones
+// rejected by excluded, is part of at least one group. Each group starts with the first uncovered pair and is extended
+// with the element which covers most of the remaining pairs, as long as excluded doesn't reject the extended group.
+func pairwise(n, size int, excluded func([]int) bool) [][]int {
+	uncovered := make(map[[2]int]bool)
+
+	var pairs [][2]int
+
+	_ = forEachCombination(n, 2, func(indices []int) error {
+		if !excluded(indices) {
+			pair := [2]int{indices[0], indices[1]}
+			uncovered[pair] = true
+			pairs = append(pairs, pair)
+		}
+
+		return nil
+	})
+
+	var groups [][]int
+
+	for _, pair := range pairs {
+		if !uncovered[pair] {
+			continue
+		}
+
+		group := []int{pair[0], pair[1]}
+		delete(uncovered, pair)
+
+		for len(group) < size {
+			best, bestGain := -1, 0
+
+			for candidate := 0; candidate < n; candidate++ {
+				gain := 0
+
+				for _, member := range group {
+					if member == candidate {
+						gain = 0
+						break
+					}
+
+					if uncovered[orderedPair(member, candidate)] {
+						gain++
+					}
+				}
+
+				if gain > bestGain && !excluded(append(group[:len(group):len(group)], candidate)) {
+					best, bestGain = candidate, gain
+				}
+			}
+
+			if best < 0 {
+				break
+			}
+
+			for _, member := range group {
+				delete(uncovered, orderedPair(member, best))
+			}
+
+			group = append(group, best)
+		}
+
+		sort.Ints(group)
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+func orderedPair(i, j int) [2]int {
+	if i > j {
+		return [2]int{j, i}
+	}
+
+	return [2]int{i, j}
+}
@@ -0,0 +1,251 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const combinationsConf = `
+ready:
+  after: 0s
+systems:
+- type: test-system
+  name: testing
+  resources:
+  - resource1
+  - resource2
+  - resource3
+  - resource4
+destroy:
+  exclusions:
+  - system: testing
+    resources:
+    - resource3
+    - resource4
+  scenarios:
+  - system: testing
+    resources:
+    - resource1
+  - system: testing
+    combinations:
+%s
+    timeout: 1m
+`
+
+func TestParseCombinations(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		combination string
+		expected    *combinations
+		expectedErr bool
+	}{
+		{
+			description: "single",
+			combination: "      mode: single",
+			expected:    &combinations{mode: singleCombinations, maxResources: 1, timeout: time.Minute},
+		},
+		{
+			description: "all with default maximum",
+			combination: "      mode: all",
+			expected:    &combinations{mode: allCombinations, maxResources: 2, timeout: time.Minute},
+		},
+		{
+			description: "pairwise with maximum and cap",
+			combination: "      mode: pairwise\n      maxResources: 3\n      maxScenarios: 10",
+			expected: &combinations{
+				mode:         pairwiseCombinations,
+				maxResources: 3,
+				maxScenarios: 10,
+				timeout:      time.Minute,
+			},
+		},
+		{
+			description: "missing mode",
+			combination: "      maxResources: 2",
+			expectedErr: true,
+		},
+		{
+			description: "unsupported mode",
+			combination: "      mode: some",
+			expectedErr: true,
+		},
+		{
+			description: "maximum for single",
+			combination: "      mode: single\n      maxResources: 2",
+			expectedErr: true,
+		},
+		{
+			description: "pairwise of single resource",
+			combination: "      mode: pairwise\n      maxResources: 1",
+			expectedErr: true,
+		},
+		{
+			description: "cap less than 1",
+			combination: "      mode: all\n      maxScenarios: 0",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(fmt.Sprintf(combinationsConf, test.combination)))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []*combinations{test.expected}, configuration.scenarioProviders["testing"].combinations)
+		})
+	}
+}
+
+func TestScenarioCombinations(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		combination string
+		expected    []Identifiers
+		expectedErr bool
+	}{
+		{
+			description: "single skips pre-defined scenario",
+			combination: "      mode: single",
+			expected: []Identifiers{
+				{TestIdentifier("resource2")},
+				{TestIdentifier("resource3")},
+				{TestIdentifier("resource4")},
+			},
+		},
+		{
+			description: "all honours exclusions",
+			combination: "      mode: all",
+			expected: []Identifiers{
+				{TestIdentifier("resource2")},
+				{TestIdentifier("resource3")},
+				{TestIdentifier("resource4")},
+				{TestIdentifier("resource1"), TestIdentifier("resource2")},
+				{TestIdentifier("resource1"), TestIdentifier("resource3")},
+				{TestIdentifier("resource1"), TestIdentifier("resource4")},
+				{TestIdentifier("resource2"), TestIdentifier("resource3")},
+				{TestIdentifier("resource2"), TestIdentifier("resource4")},
+			},
+		},
+		{
+			description: "pairwise honours exclusions",
+			combination: "      mode: pairwise\n      maxResources: 3",
+			expected: []Identifiers{
+				{TestIdentifier("resource1"), TestIdentifier("resource2"), TestIdentifier("resource3")},
+				{TestIdentifier("resource1"), TestIdentifier("resource2"), TestIdentifier("resource4")},
+			},
+		},
+		{
+			description: "more scenarios than cap",
+			combination: "      mode: all\n      maxScenarios: 5",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(fmt.Sprintf(combinationsConf, test.combination)))
+			require.NoError(t, err)
+
+			scenarios, err := configuration.scenarioProviders["testing"].pending(
+				context.Background(), configuration.systems["testing"])
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			identifiers := make([]Identifiers, 0, len(scenarios))
+			for _, scenario := range scenarios[1:] {
+				require.Equal(t, time.Minute, scenario.timeout)
+				identifiers = append(identifiers, scenario.identifiers)
+			}
+
+			require.Equal(t, test.expected, identifiers)
+		})
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	// property: every pair which isn't excluded is killed together by a group of at most size elements which isn't
+	// excluded either.
+	property := func(elements, maximum, excludedElement uint8) bool {
+		n := int(elements%12) + 2
+		size := int(maximum%4) + 2
+		excludedPair := int(excludedElement) % n
+
+		// pairs containing excludedPair element are rejected as well as groups with more than one odd element.
+		excluded := func(indices []int) bool {
+			odd := 0
+
+			for _, index := range indices {
+				if index%2 == 1 {
+					odd++
+				}
+			}
+
+			return odd > 1 || (len(indices) == 2 && (indices[0] == excludedPair || indices[1] == excludedPair))
+		}
+
+		covered := make(map[[2]int]bool)
+
+		for _, group := range pairwise(n, size, excluded) {
+			if len(group) > size || excluded(group) {
+				return false
+			}
+
+			for i := 0; i < len(group); i++ {
+				for j := i + 1; j < len(group); j++ {
+					covered[orderedPair(group[i], group[j])] = true
+				}
+			}
+		}
+
+		uncovered := 0
+
+		_ = forEachCombination(n, 2, func(indices []int) error {
+			if !excluded(indices) && !covered[[2]int{indices[0], indices[1]}] {
+				uncovered++
+			}
+
+			return nil
+		})
+
+		return uncovered == 0
+	}
+
+	require.NoError(t, quick.Check(property, nil))
+}
@@ -0,0 +1,192 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/narahari92/loki/pkg/audit"
+)
+
+// compoundScenario is a chaos scenario which kills resources of multiple systems together. It succeeds only if all the
+// involved systems get back into desired state.
+type compoundScenario struct {
+	timeout           time.Duration
+	recoveryObjective time.Duration
+	parts             []compoundPart
+}
+
+// compoundPart contains the identifiers of a single system killed by compound scenario.
+type compoundPart struct {
+	systemName  string
+	identifiers Identifiers
+}
+
+func (c *Config) parseCompoundScenario(scenarioSection map[string]interface{}) error {
+	partsConf, ok := scenarioSection[compoundKey].([]interface{})
+	if !ok {
+		return errors.Errorf("'%s' field should be of type array", compoundKey)
+	}
+
+	if len(partsConf) < 2 {
+		return errors.Errorf("'%s' scenario should involve at least two systems", compoundKey)
+	}
+
+	timeout, recoveryObjective, err := parseTimeouts(scenarioSection)
+	if err != nil {
+		return err
+	}
+
+	chaosScenario := &compoundScenario{
+		timeout:           timeout,
+		recoveryObjective: recoveryObjective,
+	}
+
+	involved := make(map[string]bool)
+
+	for _, partConf := range partsConf {
+		partSection, ok := partConf.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("malformed '%s' scenario configuration %v", compoundKey, partConf)
+		}
+
+		systemNameValue, ok := partSection[systemKey]
+		if !ok {
+			return errors.Errorf("'%s' field is mandatory in every system of '%s' scenario", systemKey, compoundKey)
+		}
+
+		systemName, ok := systemNameValue.(string)
+		if !ok {
+			return errors.Errorf(strTypeErrMsg, systemKey)
+		}
+
+		systemType, ok := c.systemNames[systemName]
+		if !ok {
+			return errors.Errorf("system '%s' referenced in a scenario is not defined", systemName)
+		}
+
+		if involved[systemName] {
+			return errors.Errorf("system '%s' is referenced more than once in '%s' scenario", systemName, compoundKey)
+		}
+
+		involved[systemName] = true
+
+		destroyer, ok := availableDestroyers[systemType]
+		if !ok {
+			return errors.Errorf("destroyer not available for system '%s' of type '%s'", systemName, systemType)
+		}
+
+		identifiers, err := destroyer.ParseDestroySection(partSection)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse scenario '%v' for system type '%s'", partSection, systemType)
+		}
+
+		chaosScenario.parts = append(chaosScenario.parts, compoundPart{
+			systemName:  systemName,
+			identifiers: identifiers,
+		})
+	}
+
+	c.compoundScenarios = append(c.compoundScenarios, chaosScenario)
+
+	return nil
+}
+
+// resolveParts resolves the identifiers of every part of compound scenario into individual resources if the system of
+// the part supports resolution.
+func (c *Config) resolveParts(ctx context.Context, chaosScenario *compoundScenario) ([]compoundPart, error) {
+	parts := make([]compoundPart, 0, len(chaosScenario.parts))
+
+	for _, part := range chaosScenario.parts {
+		resolver, ok := c.systems[part.systemName].(Resolver)
+		if !ok {
+			parts = append(parts, part)
+			continue
+		}
+
+		identifiers, err := resolver.Resolve(ctx, part.identifiers)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve identifiers of system '%s':\n%s",
+				part.systemName, part.identifiers)
+		}
+
+		if len(identifiers) == 0 {
+			return nil, errors.Errorf("scenario doesn't match any resource of system '%s':\n%s",
+				part.systemName, part.identifiers)
+		}
+
+		parts = append(parts, compoundPart{
+			systemName:  part.systemName,
+			identifiers: identifiers,
+		})
+	}
+
+	return parts, nil
+}
+
+// runCompound executes the compound scenarios one after the other once scenarios of individual systems are done, so
+// that they don't interfere with each other.
+func (cm *ChaosMaker) runCompound(ctx context.Context, run *chaosRun, targets []chaosTarget) {
+	targetsByName := make(map[string]chaosTarget)
+	for _, target := range targets {
+		targetsByName[target.systemName] = target
+	}
+
+	for i, chaosScenario := range cm.compoundScenarios {
+		if run.done(ctx) {
+			return
+		}
+
+		parts, err := cm.resolveParts(ctx, chaosScenario)
+		if err != nil {
+			cm.Reporter.AddMiscellaneous(audit.Message{
+				Result:  audit.FailureResult,
+				Message: errors.Wrap(err, "failed to generate scenario").Error(),
+			})
+			run.stop(err)
+
+			return
+		}
+
+		execution := &scenarioExecution{
+			timeout:           chaosScenario.timeout,
+			recoveryObjective: chaosScenario.recoveryObjective,
+			index:             i + 1,
+			hook:              run.hook,
+		}
+
+		for _, part := range parts {
+			execution.targets = append(execution.targets, scenarioTarget{
+				chaosTarget: targetsByName[part.systemName],
+				identifiers: part.identifiers,
+			})
+		}
+
+		logger := cm.WithField("system", joinSystemNames(execution.targets))
+
+		if err := cm.executeScenario(ctx, logger, execution); err != nil {
+			failures := run.fail(err)
+
+			if ctx.Err() == nil && cm.shouldStop(failures) {
+				logger.Warnf("stopping chaos execution after %d failed scenario(s) as per '%s' run policy",
+					failures, cm.runPolicy)
+				run.stop(nil)
+			}
+		}
+	}
+}
@@ -27,13 +27,23 @@ const (
 	nameKey             = "name"
 	typeKey             = "type"
 	readyKey            = "ready"
+	steadyStateKey      = "steadyState"
 	destroyKey          = "destroy"
 	scenariosKey        = "scenarios"
 	exclusionsKey       = "exclusions"
 	randomKey           = "random"
 	minResourcesKey     = "minResources"
 	maxResourcesKey     = "maxResources"
+	seedKey             = "seed"
 	timeoutKey          = "timeout"
+	runKey              = "run"
+	policyKey           = "policy"
+	maxFailuresKey      = "maxFailures"
+	failOnDegradedKey   = "failOnDegraded"
+	concurrencyKey      = "concurrency"
+	compoundKey         = "compound"
+	recoveryObjKey      = "recoveryObjective"
+	checkpointIntKey    = "checkpointInterval"
 	sectionUndefinedErr = "'%s' section not defined"
 	strTypeErrMsg       = "'%s' field should be of type string"
 	defaultTimeout      = 10 * time.Minute
@@ -41,11 +51,22 @@ const (
 
 // Config represents the input configuration provided to execute chaos scenarios.
 type Config struct {
-	ready             ReadyCond
-	readyTimeout      time.Duration
-	systemNames       map[string]string
-	systems           map[string]System
-	scenarioProviders map[string]*scenarioProvider
+	ready              ReadyCond
+	readyTimeout       time.Duration
+	steadyState        ReadyCond
+	steadyStateTimeout time.Duration
+	systemNames        map[string]string
+	systems            map[string]System
+	scenarioProviders  map[string]*scenarioProvider
+	runPolicy          RunPolicy
+	maxFailures        int
+	failOnDegraded     bool
+	concurrency        int
+	compoundScenarios  []*compoundScenario
+	checkpointInterval time.Duration
+	hooks              []HookOption
+	// checkpointTicker returns the ticks at which checkpoints are executed and a function which stops them.
+	checkpointTicker func(interval time.Duration) (<-chan time.Time, func())
 }
 
 // NewConfig instantiates default Config struct.
@@ -54,6 +75,9 @@ func NewConfig() *Config {
 		systemNames:       make(map[string]string),
 		systems:           make(map[string]System),
 		scenarioProviders: make(map[string]*scenarioProvider),
+		runPolicy:         FailFast,
+		concurrency:       1,
+		checkpointTicker:  newTicker,
 	}
 }
 
@@ -61,6 +85,60 @@ func (c *Config) System(name string) System {
 	return c.systems[name]
 }
 
+// SetSeed sets the seed used to generate random scenarios of all systems. Scenarios generated by same seed are same
+// as long as systems and configuration don't change.
+func (c *Config) SetSeed(seed int64) {
+	for _, provider := range c.scenarioProviders {
+		provider.seed = seed
+	}
+}
+
+// SetRunPolicy sets the policy which determines whether chaos execution continues after scenario failures. maxFailures
+// is only considered for StopAfterFailures policy.
+func (c *Config) SetRunPolicy(policy RunPolicy, maxFailures int) error {
+	if _, err := ParseRunPolicy(string(policy)); err != nil {
+		return err
+	}
+
+	if policy == StopAfterFailures && maxFailures < 1 {
+		return errors.Errorf("'%s' should be at least 1 for run policy '%s'", maxFailuresKey, StopAfterFailures)
+	}
+
+	c.runPolicy = policy
+	c.maxFailures = maxFailures
+
+	return nil
+}
+
+// SetFailOnDegraded sets whether scenarios which recover later than their recovery objective fail chaos execution.
+func (c *Config) SetFailOnDegraded(failOnDegraded bool) {
+	c.failOnDegraded = failOnDegraded
+}
+
+// SetConcurrency sets the number of systems whose chaos scenarios are executed concurrently. Scenarios of a single system
+// are always executed one after the other.
+func (c *Config) SetConcurrency(concurrency int) error {
+	if concurrency < 1 {
+		return errors.Errorf("'%s' should be at least 1", concurrencyKey)
+	}
+
+	c.concurrency = concurrency
+
+	return nil
+}
+
+// SetCheckpointInterval sets the interval at which checkpoint hook is executed while chaos scenarios are executed.
+// Checkpoints are disabled if interval is 0.
+func (c *Config) SetCheckpointInterval(interval time.Duration) error {
+	if interval < 0 {
+		return errors.Errorf("'%s' should not be negative", checkpointIntKey)
+	}
+
+	c.checkpointInterval = interval
+
+	return nil
+}
+
 // Parse parses the input configuration and populates the Config struct.
 func (c *Config) Parse(conf []byte) error {
 	yamlDefinition := make(map[string]interface{})
@@ -86,6 +164,12 @@ func (c *Config) Parse(conf []byte) error {
 		return errors.Wrapf(err, "failed to parse section '%s'", readyKey)
 	}
 
+	if steadyState, ok := yamlDefinition[steadyStateKey]; ok {
+		if err := c.parseSteadyState(steadyState); err != nil {
+			return errors.Wrapf(err, "failed to parse section '%s'", steadyStateKey)
+		}
+	}
+
 	destroy, ok := yamlDefinition[destroyKey]
 	if !ok {
 		return errors.Errorf(sectionUndefinedErr, destroyKey)
@@ -95,6 +179,18 @@ func (c *Config) Parse(conf []byte) error {
 		return errors.Wrapf(err, "failed to parse section '%s'", destroyKey)
 	}
 
+	if hooks, ok := yamlDefinition[hooksKey]; ok {
+		if err := c.parseHooks(hooks); err != nil {
+			return errors.Wrapf(err, "failed to parse section '%s'", hooksKey)
+		}
+	}
+
+	if run, ok := yamlDefinition[runKey]; ok {
+		if err := c.parseRun(run); err != nil {
+			return errors.Wrapf(err, "failed to parse section '%s'", runKey)
+		}
+	}
+
 	return nil
 }
 
@@ -150,9 +246,35 @@ func (c *Config) parseSystems(systems interface{}) error {
 }
 
 func (c *Config) parseReady(ready interface{}) error {
-	readyConf, ok := ready.(map[string]interface{})
+	readyCond, timeout, err := c.parseReadyCond(readyKey, ready)
+	if err != nil {
+		return err
+	}
+
+	c.ready = readyCond
+	c.readyTimeout = timeout
+
+	return nil
+}
+
+func (c *Config) parseSteadyState(steadyState interface{}) error {
+	steadyStateCond, timeout, err := c.parseReadyCond(steadyStateKey, steadyState)
+	if err != nil {
+		return err
+	}
+
+	c.steadyState = steadyStateCond
+	c.steadyStateTimeout = timeout
+
+	return nil
+}
+
+// parseReadyCond parses a section defining ReadyCond using registered ready parsers along with the timeout within
+// which the condition should be met.
+func (c *Config) parseReadyCond(section string, conf interface{}) (ReadyCond, time.Duration, error) {
+	readyConf, ok := conf.(map[string]interface{})
 	if !ok {
-		return errors.Errorf("'%s' section should be of type map", readyKey)
+		return nil, 0, errors.Errorf("'%s' section should be of type map", section)
 	}
 
 	var err error
@@ -161,30 +283,16 @@ func (c *Config) parseReady(ready interface{}) error {
 	if timeoutValue, ok := readyConf[timeoutKey]; ok {
 		timeout, err = parseDuration(timeoutKey, timeoutValue)
 		if err != nil {
-			return errors.Wrap(err, "failed to parse ready timeout")
+			return nil, 0, errors.Wrapf(err, "failed to parse %s timeout", section)
 		}
 	}
 
-	c.readyTimeout = timeout
-
-	for key, parser := range readyParsers {
-		if _, ok := readyConf[key]; !ok {
-			continue
-		}
-
-		readyParser := parser(c)
-
-		readyCond, err := readyParser.Parse(readyConf)
-		if err != nil {
-			return errors.Wrapf(err, "failed to parse ready section using '%s' ready parser", key)
-		}
-
-		c.ready = readyCond
-
-		return nil
+	readyCond, err := c.parseCondition(readyConf)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to parse %s section", section)
 	}
 
-	return errors.New("unidentified ready section")
+	return readyCond, timeout, nil
 }
 
 func (c *Config) parseDestroy(destroy interface{}) error {
@@ -218,12 +326,12 @@ func (c *Config) parseExclusions(exclusions interface{}) error {
 	}
 
 	for _, exclusionConf := range exclusionConfigs {
-		exclusion, ok := exclusionConf.(map[string]interface{})
+		exclusionSection, ok := exclusionConf.(map[string]interface{})
 		if !ok {
 			return errors.Errorf("malformed exclusion configuration %v", exclusionConf)
 		}
 
-		systemNameValue, ok := exclusion[systemKey]
+		systemNameValue, ok := exclusionSection[systemKey]
 		if !ok {
 			return errors.Errorf("'%s' field is mandatory in exclusion", systemKey)
 		}
@@ -243,13 +351,22 @@ func (c *Config) parseExclusions(exclusions interface{}) error {
 			return errors.Errorf("destroyer not available for system '%s' of type '%s'", systemName, systemType)
 		}
 
-		identifiers, err := destroyer.ParseDestroySection(exclusion)
+		mode, atMost, err := parseExclusionMode(exclusionSection)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse exclusion '%v' for system type '%s'", exclusion, systemType)
+			return err
+		}
+
+		identifiers, err := destroyer.ParseDestroySection(exclusionSection)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse exclusion '%v' for system type '%s'", exclusionSection, systemType)
 		}
 
 		scenarioProvider := c.scenarioProvider(systemName)
-		scenarioProvider.exclusions = append(scenarioProvider.exclusions, identifiers)
+		scenarioProvider.exclusions = append(scenarioProvider.exclusions, &exclusion{
+			mode:        mode,
+			atMost:      atMost,
+			identifiers: identifiers,
+		})
 	}
 
 	return nil
@@ -267,6 +384,14 @@ func (c *Config) parseScenarios(scenarios interface{}) error {
 			return errors.Errorf("malformed scenario configuration %v", scenarioConf)
 		}
 
+		if _, ok := scenarioSection[compoundKey]; ok {
+			if err := c.parseCompoundScenario(scenarioSection); err != nil {
+				return errors.Wrapf(err, "failed to parse '%s' scenario", compoundKey)
+			}
+
+			continue
+		}
+
 		systemNameValue, ok := scenarioSection[systemKey]
 		if !ok {
 			return errors.Errorf("'%s' field is mandatory in scenario", systemKey)
@@ -287,17 +412,24 @@ func (c *Config) parseScenarios(scenarios interface{}) error {
 				return errors.Wrapf(err, "failed to parse 'random' scenario for system '%s'", systemName)
 			}
 
-			return nil
+			continue
 		}
 
-		var err error
-
-		timeout := defaultTimeout
-		if timeoutValue, ok := scenarioSection[timeoutKey]; ok {
-			timeout, err = parseDuration(timeoutKey, timeoutValue)
+		if _, ok := scenarioSection[combinationsKey]; ok {
+			chaosCombinations, err := parseCombinations(scenarioSection)
 			if err != nil {
-				return err
+				return errors.Wrapf(err, "failed to parse '%s' scenario for system '%s'", combinationsKey, systemName)
 			}
+
+			scenarioProvider := c.scenarioProvider(systemName)
+			scenarioProvider.combinations = append(scenarioProvider.combinations, chaosCombinations)
+
+			continue
+		}
+
+		timeout, recoveryObjective, err := parseTimeouts(scenarioSection)
+		if err != nil {
+			return err
 		}
 
 		destroyer, ok := availableDestroyers[systemType]
@@ -311,8 +443,9 @@ func (c *Config) parseScenarios(scenarios interface{}) error {
 		}
 
 		chaosScenario := &scenario{
-			timeout:     timeout,
-			identifiers: identifiers,
+			timeout:           timeout,
+			recoveryObjective: recoveryObjective,
+			identifiers:       identifiers,
 		}
 
 		scenarioProvider := c.scenarioProvider(systemName)
@@ -323,21 +456,26 @@ func (c *Config) parseScenarios(scenarios interface{}) error {
 }
 
 func (c *Config) parseRandomScenario(systemName string, scenario map[string]interface{}) error {
-	randomValue, ok := (scenario[randomKey]).(float64)
-	if !ok {
-		return errors.Errorf("'%s' field should be of type float", randomKey)
-	}
-
-	random := int64(randomValue)
-
-	var err error
-
-	timeout := defaultTimeout
-	if timeoutValue, ok := scenario[timeoutKey]; ok {
-		timeout, err = parseDuration(timeoutKey, timeoutValue)
-		if err != nil {
+	var (
+		random    int64
+		chaosSoak *soak
+		err       error
+	)
+
+	switch randomValue := scenario[randomKey].(type) {
+	case float64:
+		random = int64(randomValue)
+	case map[string]interface{}:
+		if chaosSoak, err = parseSoak(randomValue); err != nil {
 			return err
 		}
+	default:
+		return errors.Errorf("'%s' field should be of type int or map", randomKey)
+	}
+
+	timeout, recoveryObjective, err := parseTimeouts(scenario)
+	if err != nil {
+		return err
 	}
 
 	minimum := int64(1)
@@ -350,7 +488,12 @@ func (c *Config) parseRandomScenario(systemName string, scenario map[string]inte
 		minimum = int64(minResources)
 	}
 
-	maximum := int64(5)
+	if minimum < 1 {
+		return errors.Errorf("'%s' should be at least 1", minResourcesKey)
+	}
+
+	// maximum is left 0 when not defined, and is then computed from the identifiers of system.
+	var maximum int64
 	if maxValue, ok := scenario[maxResourcesKey]; ok {
 		maxResources, ok := maxValue.(float64)
 		if !ok {
@@ -358,28 +501,158 @@ func (c *Config) parseRandomScenario(systemName string, scenario map[string]inte
 		}
 
 		maximum = int64(maxResources)
+
+		if maximum < minimum {
+			return errors.Errorf("'%s' %d should not be less than '%s' %d", maxResourcesKey, maximum,
+				minResourcesKey, minimum)
+		}
 	}
 
 	scenarioProvider := c.scenarioProvider(systemName)
+
+	if seedValue, ok := scenario[seedKey]; ok {
+		seed, ok := seedValue.(float64)
+		if !ok {
+			return errors.Errorf("'%s' field should be of type int", seedKey)
+		}
+
+		scenarioProvider.seed = int64(seed)
+	}
+
+	chaosSelection, err := parseSelection(scenario)
+	if err != nil {
+		return err
+	}
+
 	scenarioProvider.random = random
+	scenarioProvider.selection = chaosSelection
+	scenarioProvider.soak = chaosSoak
 	scenarioProvider.minResources = minimum
 	scenarioProvider.maxResources = maximum
 	scenarioProvider.randomTimeout = timeout
+	scenarioProvider.randomObjective = recoveryObjective
 
 	return nil
 }
 
+func (c *Config) parseRun(run interface{}) error {
+	runConf, ok := run.(map[string]interface{})
+	if !ok {
+		return errors.Errorf("'%s' section should be of type map", runKey)
+	}
+
+	policy := c.runPolicy
+	if policyValue, ok := runConf[policyKey]; ok {
+		policyName, ok := policyValue.(string)
+		if !ok {
+			return errors.Errorf(strTypeErrMsg, policyKey)
+		}
+
+		runPolicy, err := ParseRunPolicy(policyName)
+		if err != nil {
+			return err
+		}
+
+		policy = runPolicy
+	}
+
+	maxFailures := c.maxFailures
+	if maxFailuresValue, ok := runConf[maxFailuresKey]; ok {
+		failures, ok := maxFailuresValue.(float64)
+		if !ok {
+			return errors.Errorf("'%s' field should be of type int", maxFailuresKey)
+		}
+
+		maxFailures = int(failures)
+	}
+
+	if failOnDegradedValue, ok := runConf[failOnDegradedKey]; ok {
+		failOnDegraded, ok := failOnDegradedValue.(bool)
+		if !ok {
+			return errors.Errorf("'%s' field should be of type bool", failOnDegradedKey)
+		}
+
+		c.failOnDegraded = failOnDegraded
+	}
+
+	if concurrencyValue, ok := runConf[concurrencyKey]; ok {
+		concurrency, ok := concurrencyValue.(float64)
+		if !ok {
+			return errors.Errorf("'%s' field should be of type int", concurrencyKey)
+		}
+
+		if err := c.SetConcurrency(int(concurrency)); err != nil {
+			return err
+		}
+	}
+
+	if checkpointValue, ok := runConf[checkpointIntKey]; ok {
+		interval, err := parseDuration(checkpointIntKey, checkpointValue)
+		if err != nil {
+			return err
+		}
+
+		if err := c.SetCheckpointInterval(interval); err != nil {
+			return err
+		}
+	}
+
+	return c.SetRunPolicy(policy, maxFailures)
+}
+
+// shouldStop determines whether chaos execution should stop after given number of scenario failures.
+func (c *Config) shouldStop(failures int) bool {
+	switch c.runPolicy {
+	case RunAll:
+		return false
+	case StopAfterFailures:
+		return failures >= c.maxFailures
+	default:
+		return failures > 0
+	}
+}
+
 func (c *Config) scenarioProvider(systemName string) *scenarioProvider {
 	if scenarioProvider, ok := c.scenarioProviders[systemName]; ok {
 		return scenarioProvider
 	}
 
-	scenarioProvider := &scenarioProvider{}
+	scenarioProvider := &scenarioProvider{
+		seed: newSeed(),
+	}
 	c.scenarioProviders[systemName] = scenarioProvider
 
 	return scenarioProvider
 }
 
+// parseTimeouts parses the timeout within which system should recover from scenario and the optional recovery objective
+// within which system is expected to recover.
+func parseTimeouts(scenario map[string]interface{}) (time.Duration, time.Duration, error) {
+	var err error
+
+	timeout := defaultTimeout
+	if timeoutValue, ok := scenario[timeoutKey]; ok {
+		timeout, err = parseDuration(timeoutKey, timeoutValue)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var recoveryObjective time.Duration
+	if recoveryObjectiveValue, ok := scenario[recoveryObjKey]; ok {
+		recoveryObjective, err = parseDuration(recoveryObjKey, recoveryObjectiveValue)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if recoveryObjective > timeout {
+			return 0, 0, errors.Errorf("'%s' should not exceed '%s'", recoveryObjKey, timeoutKey)
+		}
+	}
+
+	return timeout, recoveryObjective, nil
+}
+
 func parseDuration(fieldName string, value interface{}) (time.Duration, error) {
 	durationValue, ok := value.(string)
 	if !ok {
@@ -15,8 +15,10 @@ limitations under the License.
 package loki
 
 import (
+	"context"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -32,3 +34,217 @@ func TestParse(t *testing.T) {
 	err = configuration.Parse([]byte(conf))
 	require.NoError(t, err)
 }
+
+func TestParseRun(t *testing.T) {
+	RegisterTestSystem(t)
+
+	conf, err := ioutil.ReadFile("../../testdata/sample-config.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		description string
+		run         string
+		policy      RunPolicy
+		maxFailures int
+		concurrency int
+		expectedErr bool
+	}{
+		{
+			description: "default run policy",
+			policy:      FailFast,
+			concurrency: 1,
+		},
+		{
+			description: "concurrency",
+			run:         "run:\n  concurrency: 4\n",
+			policy:      FailFast,
+			concurrency: 4,
+		},
+		{
+			description: "invalid concurrency",
+			run:         "run:\n  concurrency: 0\n",
+			expectedErr: true,
+		},
+		{
+			description: "negative concurrency",
+			run:         "run:\n  concurrency: -2\n",
+			expectedErr: true,
+		},
+		{
+			description: "run all policy",
+			run:         "run:\n  policy: runAll\n",
+			policy:      RunAll,
+			concurrency: 1,
+		},
+		{
+			description: "stop after failures policy",
+			run:         "run:\n  policy: stopAfterFailures\n  maxFailures: 3\n",
+			policy:      StopAfterFailures,
+			maxFailures: 3,
+			concurrency: 1,
+		},
+		{
+			description: "stop after failures policy without max failures",
+			run:         "run:\n  policy: stopAfterFailures\n",
+			expectedErr: true,
+		},
+		{
+			description: "unidentified policy",
+			run:         "run:\n  policy: sometimes\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse(append([]byte(test.run), conf...))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.policy, configuration.runPolicy)
+			require.Equal(t, test.maxFailures, configuration.maxFailures)
+			require.Equal(t, test.concurrency, configuration.concurrency)
+		})
+	}
+}
+
+func TestSetConcurrency(t *testing.T) {
+	configuration := NewConfig()
+
+	require.Error(t, configuration.SetConcurrency(0))
+	require.Error(t, configuration.SetConcurrency(-1))
+	require.Equal(t, 1, configuration.concurrency)
+
+	require.NoError(t, configuration.SetConcurrency(3))
+	require.Equal(t, 3, configuration.concurrency)
+}
+
+func TestParseRecoveryObjective(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description       string
+		scenario          string
+		recoveryObjective time.Duration
+		expectedErr       bool
+	}{
+		{
+			description: "without recovery objective",
+			scenario:    "  - system: testing\n    resources:\n    - resource1\n",
+		},
+		{
+			description:       "pre-defined scenario",
+			scenario:          "  - system: testing\n    timeout: 5m\n    recoveryObjective: 30s\n    resources:\n    - resource1\n",
+			recoveryObjective: 30 * time.Second,
+		},
+		{
+			description:       "random scenario",
+			scenario:          "  - system: testing\n    random: 2\n    recoveryObjective: 1m\n",
+			recoveryObjective: time.Minute,
+		},
+		{
+			description: "recovery objective exceeding timeout",
+			scenario:    "  - system: testing\n    timeout: 1m\n    recoveryObjective: 2m\n    resources:\n    - resource1\n",
+			expectedErr: true,
+		},
+		{
+			description: "malformed recovery objective",
+			scenario:    "  - system: testing\n    recoveryObjective: soon\n    resources:\n    - resource1\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			conf := "ready:\n  after: 0s\nsystems:\n- type: test-system\n  name: testing\n  resources:\n  - resource1\n" +
+				"  - resource2\n" +
+				"destroy:\n  scenarios:\n" + test.scenario
+
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(conf))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			scenarios, err := configuration.scenarioProviders["testing"].pending(
+				context.Background(), configuration.systems["testing"])
+			require.NoError(t, err)
+			require.NotEqual(t, 0, len(scenarios))
+
+			for _, scenario := range scenarios {
+				require.Equal(t, test.recoveryObjective, scenario.recoveryObjective)
+			}
+		})
+	}
+}
+
+func TestParseCompound(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		scenario    string
+		expectedErr bool
+	}{
+		{
+			description: "compound scenario",
+			scenario: "  - compound:\n    - system: testing\n      resources:\n      - resource1\n" +
+				"    - system: other\n      resources:\n      - resource1\n    timeout: 5m\n",
+		},
+		{
+			description: "single system",
+			scenario:    "  - compound:\n    - system: testing\n      resources:\n      - resource1\n",
+			expectedErr: true,
+		},
+		{
+			description: "repeated system",
+			scenario: "  - compound:\n    - system: testing\n      resources:\n      - resource1\n" +
+				"    - system: testing\n      resources:\n      - resource1\n",
+			expectedErr: true,
+		},
+		{
+			description: "undefined system",
+			scenario: "  - compound:\n    - system: testing\n      resources:\n      - resource1\n" +
+				"    - system: unknown\n      resources:\n      - resource1\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			conf := "ready:\n  after: 0s\nsystems:\n- type: test-system\n  name: testing\n  resources:\n  - resource1\n" +
+				"  - resource2\n" +
+				"- type: test-system\n  name: other\n  resources:\n  - resource1\n" +
+				"destroy:\n  scenarios:\n" + test.scenario
+
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(conf))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 1, len(configuration.compoundScenarios))
+
+			parts := configuration.compoundScenarios[0].parts
+			require.Equal(t, 2, len(parts))
+			require.Equal(t, "testing", parts[0].systemName)
+			require.Equal(t, "other", parts[1].systemName)
+			require.Equal(t, 5*time.Minute, configuration.compoundScenarios[0].timeout)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+// DriftType classifies the way in which a resource deviates from its desired state.
+type DriftType string
+
+const (
+	// MissingDrift indicates that resource of desired state doesn't exist.
+	MissingDrift DriftType = "missing"
+	// ExtraDrift indicates that resource exists which isn't part of desired state. It is informational and doesn't fail
+	// validation by itself.
+	ExtraDrift DriftType = "extra"
+	// LabelDrift indicates that labels of resource differ from desired state.
+	LabelDrift DriftType = "label"
+	// StatusDrift indicates that status of resource differs from desired state.
+	StatusDrift DriftType = "status"
+	// ConditionDrift indicates that status conditions of resource differ from desired state.
+	ConditionDrift DriftType = "condition"
+)
+
+// Drift describes how a single resource deviates from its desired state.
+type Drift struct {
+	// ID of the resource which drifted.
+	ID ID
+	// Type classifies the drift.
+	Type DriftType
+	// Message describes the drift in detail.
+	Message string
+}
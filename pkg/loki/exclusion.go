@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	modeKey   = "mode"
+	atMostKey = "atMost"
+)
+
+// exclusionMode determines which generated scenarios are rejected by an exclusion.
+type exclusionMode string
+
+const (
+	// neverExclusion rejects any scenario which kills even one of the resources of exclusion.
+	neverExclusion exclusionMode = "never"
+	// notTogetherExclusion rejects scenarios which kill exactly the resources of exclusion, no more and no less.
+	notTogetherExclusion exclusionMode = "notTogether"
+	// atMostExclusion rejects scenarios which kill more than the configured number of resources of exclusion.
+	atMostExclusion exclusionMode = "atMost"
+)
+
+// exclusion is the group of resources of a system which generated scenarios shouldn't kill as per its mode.
+type exclusion struct {
+	mode        exclusionMode
+	atMost      int
+	identifiers Identifiers
+}
+
+// parseExclusionMode parses the mode of exclusion along with the limit of resources for atMost mode. Mode defaults to
+// notTogether, or atMost when only the limit is defined.
+func parseExclusionMode(exclusionConf map[string]interface{}) (exclusionMode, int, error) {
+	mode := notTogetherExclusion
+	if modeValue, ok := exclusionConf[modeKey]; ok {
+		modeName, ok := modeValue.(string)
+		if !ok {
+			return "", 0, errors.Errorf(strTypeErrMsg, modeKey)
+		}
+
+		switch exclusionMode(modeName) {
+		case neverExclusion, notTogetherExclusion, atMostExclusion:
+			mode = exclusionMode(modeName)
+		default:
+			return "", 0, errors.Errorf("unidentified exclusion %s '%s', should be one of '%s', '%s' or '%s'",
+				modeKey, modeName, neverExclusion, notTogetherExclusion, atMostExclusion)
+		}
+	}
+
+	atMostValue, ok := exclusionConf[atMostKey]
+	if !ok {
+		if mode == atMostExclusion {
+			return "", 0, errors.Errorf("'%s' field is mandatory in exclusion of mode '%s'", atMostKey, atMostExclusion)
+		}
+
+		return mode, 0, nil
+	}
+
+	if _, ok := exclusionConf[modeKey]; ok && mode != atMostExclusion {
+		return "", 0, errors.Errorf("'%s' field is only supported in exclusion of mode '%s'", atMostKey, atMostExclusion)
+	}
+
+	atMost, ok := atMostValue.(float64)
+	if !ok {
+		return "", 0, errors.Errorf("'%s' field should be of type int", atMostKey)
+	}
+
+	if atMost < 0 {
+		return "", 0, errors.Errorf("'%s' should not be negative", atMostKey)
+	}
+
+	return atMostExclusion, int(atMost), nil
+}
+
+// excludes returns whether scenario killing given identifiers is rejected by exclusion.
+func (e *exclusion) excludes(identifiers Identifiers) bool {
+	excluded := make(map[ID]bool, len(e.identifiers))
+
+	for _, identifier := range e.identifiers {
+		excluded[identifier.ID()] = false
+	}
+
+	matches := 0
+	killed := make(map[ID]bool, len(identifiers))
+
+	for _, identifier := range identifiers {
+		killed[identifier.ID()] = true
+
+		if matched, ok := excluded[identifier.ID()]; ok && !matched {
+			excluded[identifier.ID()] = true
+			matches++
+		}
+	}
+
+	switch e.mode {
+	case neverExclusion:
+		return matches > 0
+	case atMostExclusion:
+		return matches > e.atMost
+	default:
+		// exclusion which doesn't match any resource, for example an unmatched selector, doesn't reject anything.
+		return len(excluded) > 0 && matches == len(excluded) && len(killed) == len(excluded)
+	}
+}
+
+// excluded returns whether scenario killing given identifiers is rejected by any exclusion of provider.
+func (sp *scenarioProvider) excluded(identifiers Identifiers) bool {
+	for _, exclusion := range sp.exclusions {
+		if exclusion.excludes(identifiers) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,218 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const exclusionConf = `
+ready:
+  after: 0s
+systems:
+- type: test-system
+  name: testing
+  resources:
+  - resource1
+  - resource2
+  - resource3
+  - resource4
+destroy:
+  exclusions:
+  - system: testing
+    resources:
+    - resource1
+    - resource2
+%s
+  scenarios:
+  - system: testing
+    random: 2
+    minResources: 1
+    maxResources: 2
+    seed: 42
+`
+
+func TestParseExclusion(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		mode        string
+		expected    *exclusion
+		expectedErr bool
+	}{
+		{
+			description: "not together by default",
+			expected:    &exclusion{mode: notTogetherExclusion},
+		},
+		{
+			description: "never",
+			mode:        "    mode: never",
+			expected:    &exclusion{mode: neverExclusion},
+		},
+		{
+			description: "at most",
+			mode:        "    mode: atMost\n    atMost: 1",
+			expected:    &exclusion{mode: atMostExclusion, atMost: 1},
+		},
+		{
+			description: "at most without mode",
+			mode:        "    atMost: 1",
+			expected:    &exclusion{mode: atMostExclusion, atMost: 1},
+		},
+		{
+			description: "unidentified mode",
+			mode:        "    mode: sometimes",
+			expectedErr: true,
+		},
+		{
+			description: "at most mode without limit",
+			mode:        "    mode: atMost",
+			expectedErr: true,
+		},
+		{
+			description: "limit with other mode",
+			mode:        "    mode: never\n    atMost: 1",
+			expectedErr: true,
+		},
+		{
+			description: "negative limit",
+			mode:        "    atMost: -1",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(fmt.Sprintf(exclusionConf, test.mode)))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			test.expected.identifiers = Identifiers{TestIdentifier("resource1"), TestIdentifier("resource2")}
+			require.Equal(t, []*exclusion{test.expected}, configuration.scenarioProviders["testing"].exclusions)
+		})
+	}
+}
+
+func TestExclusionExcludes(t *testing.T) {
+	group := Identifiers{TestIdentifier("resource1"), TestIdentifier("resource2"), TestIdentifier("resource3")}
+
+	tests := []struct {
+		description string
+		exclusion   *exclusion
+		identifiers Identifiers
+		expected    bool
+	}{
+		{
+			description: "never with one resource of group",
+			exclusion:   &exclusion{mode: neverExclusion, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource4"), TestIdentifier("resource2")},
+			expected:    true,
+		},
+		{
+			description: "never without resources of group",
+			exclusion:   &exclusion{mode: neverExclusion, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource4")},
+			expected:    false,
+		},
+		{
+			description: "not together with exact group",
+			exclusion:   &exclusion{mode: notTogetherExclusion, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource3"), TestIdentifier("resource1"), TestIdentifier("resource2")},
+			expected:    true,
+		},
+		{
+			description: "not together with repeated resource of group",
+			exclusion:   &exclusion{mode: notTogetherExclusion, identifiers: group[:1]},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource1")},
+			expected:    true,
+		},
+		{
+			description: "not together with group among other resources",
+			exclusion:   &exclusion{mode: notTogetherExclusion, identifiers: group[:1]},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource4")},
+			expected:    false,
+		},
+		{
+			description: "not together with part of group",
+			exclusion:   &exclusion{mode: notTogetherExclusion, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource2")},
+			expected:    false,
+		},
+		{
+			description: "not together with unmatched group",
+			exclusion:   &exclusion{mode: notTogetherExclusion},
+			identifiers: Identifiers{TestIdentifier("resource1")},
+			expected:    false,
+		},
+		{
+			description: "at most within limit",
+			exclusion:   &exclusion{mode: atMostExclusion, atMost: 2, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource3"), TestIdentifier("resource4")},
+			expected:    false,
+		},
+		{
+			description: "at most beyond limit",
+			exclusion:   &exclusion{mode: atMostExclusion, atMost: 1, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource3")},
+			expected:    true,
+		},
+		{
+			description: "at most counts repeated resource once",
+			exclusion:   &exclusion{mode: atMostExclusion, atMost: 1, identifiers: group},
+			identifiers: Identifiers{TestIdentifier("resource1"), TestIdentifier("resource1")},
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			require.Equal(t, test.expected, test.exclusion.excludes(test.identifiers))
+		})
+	}
+}
+
+func TestScenarioExclusions(t *testing.T) {
+	RegisterTestSystem(t)
+
+	configuration := NewConfig()
+
+	err := configuration.Parse([]byte(fmt.Sprintf(exclusionConf, "    mode: never")))
+	require.NoError(t, err)
+
+	provider := configuration.scenarioProviders["testing"]
+
+	scenarios, err := provider.pending(context.Background(), configuration.systems["testing"])
+	require.NoError(t, err)
+	require.Equal(t, Identifiers{TestIdentifier("resource3"), TestIdentifier("resource4")}, provider.allIdentifiers)
+	require.Equal(t, 2, len(scenarios))
+
+	for _, scenario := range scenarios {
+		require.NotContains(t, scenario.identifiers, TestIdentifier("resource1"))
+		require.NotContains(t, scenario.identifiers, TestIdentifier("resource2"))
+	}
+}
@@ -14,17 +14,80 @@ limitations under the License.
 
 package loki
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	hooksKey          = "hooks"
+	preReadyKey       = "preReady"
+	postReadyKey      = "postReady"
+	preSystemLoadKey  = "preSystemLoad"
+	postSystemLoadKey = "postSystemLoad"
+	preChaosKey       = "preChaos"
+	postChaosKey      = "postChaos"
+	preScenarioKey    = "preScenario"
+	postScenarioKey   = "postScenario"
+	scenarioFailKey   = "scenarioFailure"
+)
+
+type scenarioKey struct{}
+
+// hookPhases maps the phases of hooks section to the functional options which hook actions into them.
+var hookPhases = map[string]func(func(context.Context) error) HookOption{
+	preReadyKey:       WithPreReady,
+	postReadyKey:      WithPostReady,
+	preSystemLoadKey:  WithPreSystemLoad,
+	postSystemLoadKey: WithPostSystemLoad,
+	preChaosKey:       WithPreChaos,
+	postChaosKey:      WithPostChaos,
+	preScenarioKey:    WithPreScenario,
+	postScenarioKey:   WithPostScenario,
+	scenarioFailKey:   WithScenarioFailure,
+}
 
 // Hook can be used to perform user defined actions at different stages of chaos execution such as log collection,
 // fetching system state etc.
 type Hook struct {
-	preReady       func(context.Context) error
-	postReady      func(context.Context) error
-	preSystemLoad  func(context.Context) error
-	postSystemLoad func(context.Context) error
-	preChaos       func(context.Context) error
-	postChaos      func(context.Context) error
+	preReady        func(context.Context) error
+	postReady       func(context.Context) error
+	preSystemLoad   func(context.Context) error
+	postSystemLoad  func(context.Context) error
+	preChaos        func(context.Context) error
+	postChaos       func(context.Context) error
+	preScenario     func(context.Context) error
+	postScenario    func(context.Context) error
+	scenarioFailure func(context.Context) error
+	checkpoint      func(context.Context) error
+}
+
+// ScenarioInfo describes the chaos scenario for which scenario hook is executed.
+type ScenarioInfo struct {
+	// System is the name of system of scenario. Names of all systems involved are separated by comma for compound
+	// scenarios.
+	System string
+	// Index is the position of scenario, starting from 1, among scenarios of its system or among compound scenarios.
+	Index int
+	// Identifiers are the identifiers killed by scenario.
+	Identifiers Identifiers
+}
+
+// ScenarioFromContext returns the description of chaos scenario passed to scenario hooks through ctx. It returns false
+// if ctx doesn't belong to a scenario hook.
+func ScenarioFromContext(ctx context.Context) (ScenarioInfo, bool) {
+	info, ok := ctx.Value(scenarioKey{}).(ScenarioInfo)
+	return info, ok
+}
+
+// ContextWithScenario returns the context passed to scenario hooks of given scenario. It can be used to test scenario
+// hooks outside of chaos execution.
+func ContextWithScenario(ctx context.Context, info ScenarioInfo) context.Context {
+	return context.WithValue(ctx, scenarioKey{}, info)
 }
 
 // HookOption is functional option implementation to create Hook.
@@ -71,3 +134,130 @@ func WithPostChaos(postChaos func(context.Context) error) HookOption {
 		h.postChaos = postChaos
 	}
 }
+
+// WithPreScenario functional option is used to hook user defined action to be executed before identifiers of every chaos
+// scenario are killed. Description of scenario is available through ScenarioFromContext.
+func WithPreScenario(preScenario func(context.Context) error) HookOption {
+	return func(h *Hook) {
+		h.preScenario = preScenario
+	}
+}
+
+// WithPostScenario functional option is used to hook user defined action to be executed after systems recover from
+// every chaos scenario. Description of scenario is available through ScenarioFromContext.
+func WithPostScenario(postScenario func(context.Context) error) HookOption {
+	return func(h *Hook) {
+		h.postScenario = postScenario
+	}
+}
+
+// WithScenarioFailure functional option is used to hook user defined action to be executed when a chaos scenario fails,
+// before resources are restored. Description of scenario is available through ScenarioFromContext.
+func WithScenarioFailure(scenarioFailure func(context.Context) error) HookOption {
+	return func(h *Hook) {
+		h.scenarioFailure = scenarioFailure
+	}
+}
+
+// WithCheckpoint functional option is used to hook user defined action to be executed periodically at checkpoint
+// interval of Config while chaos scenarios are executed, such as writing report of scenarios executed so far.
+func WithCheckpoint(checkpoint func(context.Context) error) HookOption {
+	return func(h *Hook) {
+		h.checkpoint = checkpoint
+	}
+}
+
+func (c *Config) parseHooks(hooks interface{}) error {
+	hooksConf, ok := hooks.(map[string]interface{})
+	if !ok {
+		return errors.Errorf("'%s' section should be of type map", hooksKey)
+	}
+
+	phases := make([]string, 0, len(hooksConf))
+	for phase := range hooksConf {
+		phases = append(phases, phase)
+	}
+
+	sort.Strings(phases)
+
+	for _, phase := range phases {
+		hookOption, ok := hookPhases[phase]
+		if !ok {
+			return errors.Errorf("unidentified hook phase '%s'", phase)
+		}
+
+		var hookConfs []interface{}
+
+		switch phaseConf := hooksConf[phase].(type) {
+		case map[string]interface{}:
+			hookConfs = []interface{}{phaseConf}
+		case []interface{}:
+			hookConfs = phaseConf
+		default:
+			return errors.Errorf("'%s' hooks should be of type map or array", phase)
+		}
+
+		phaseHooks := make([]func(context.Context) error, 0, len(hookConfs))
+
+		for i, hookConf := range hookConfs {
+			hookDefinition, ok := hookConf.(map[string]interface{})
+			if !ok {
+				return errors.Errorf("hook %d of '%s' should be of type map", i+1, phase)
+			}
+
+			hook, err := c.parseHook(hookDefinition)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse hook %d of '%s'", i+1, phase)
+			}
+
+			phaseHooks = append(phaseHooks, hook)
+		}
+
+		c.hooks = append(c.hooks, hookOption(sequence(phaseHooks)))
+	}
+
+	return nil
+}
+
+// parseHook creates the hook action using the registered hook parser whose key is present in hook definition.
+func (c *Config) parseHook(hookConf map[string]interface{}) (func(context.Context) error, error) {
+	for key, parser := range hookParsers {
+		if _, ok := hookConf[key]; !ok {
+			continue
+		}
+
+		hook, err := parser(c).Parse(hookConf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse hook using '%s' hook parser", key)
+		}
+
+		return hook, nil
+	}
+
+	return nil, errors.New("unidentified hook")
+}
+
+// sequence combines hooks of a phase into single hook which executes them one after the other. All hooks are executed
+// even if some of them fail, so that diagnostics collected by later hooks aren't lost, and their errors are returned
+// together.
+func sequence(hooks []func(context.Context) error) func(context.Context) error {
+	if len(hooks) == 1 {
+		return hooks[0]
+	}
+
+	return func(ctx context.Context) error {
+		var failures []string
+
+		for i, hook := range hooks {
+			if err := hook(ctx); err != nil {
+				failures = append(failures, fmt.Sprintf("hook %d: %s", i+1, err))
+			}
+		}
+
+		if len(failures) > 0 {
+			return errors.New(strings.Join(failures, "; "))
+		}
+
+		return nil
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+
+	"github.com/narahari92/loki/pkg/audit"
 )
 
 func TestHooks(t *testing.T) {
@@ -127,3 +129,98 @@ func TestHooks(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedHookMessages, hookMessages)
 }
+
+func TestHooksSection(t *testing.T) {
+	RegisterHookParser("echo", func(*Config) HookParser {
+		return HookParserFunc(func(hookConf map[string]interface{}) (func(context.Context) error, error) {
+			message, ok := hookConf["echo"].(string)
+			if !ok {
+				return nil, errors.New("'echo' field should be of type string")
+			}
+
+			return func(ctx context.Context) error {
+				RecordOutput(ctx, message)
+				return nil
+			}, nil
+		})
+	})
+	registerChaosTestSystem("hooked-test-system", nil, nil)
+
+	conf := `
+ready:
+  after: 0s
+systems:
+- type: hooked-test-system
+  name: hooked
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: hooked
+    resources:
+    - resource1
+`
+	hooks := `
+hooks:
+  preReady:
+    echo: checking readiness
+  postReady:
+  - echo: ready
+  - echo: loading systems
+  preChaos:
+    echo: starting chaos
+  postChaos:
+    echo: chaos completed
+`
+	chaosMaker := newChaosMaker(t, conf+hooks)
+
+	overridden := false
+
+	err := chaosMaker.CreateChaos(context.Background(), WithPostChaos(func(context.Context) error {
+		overridden = true
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, true, overridden)
+
+	require.Equal(t, audit.SuccessResult, chaosMaker.Reporter.Ready.PreReady.Result)
+	require.Equal(t, "checking readiness", chaosMaker.Reporter.Ready.PreReady.Output)
+	require.Equal(t, "ready\nloading systems", chaosMaker.Reporter.Ready.PostReady.Output)
+	require.Equal(t, "", chaosMaker.Reporter.Load.PreLoad.Result)
+	require.Equal(t, "starting chaos", chaosMaker.Reporter.Scenarios.PreChaosTests.Output)
+	require.Equal(t, audit.SuccessResult, chaosMaker.Reporter.Scenarios.PostChaosTests.Result)
+	require.Equal(t, "", chaosMaker.Reporter.Scenarios.PostChaosTests.Output)
+
+	for _, hooks := range []string{
+		"hooks:\n  preChaos: echo\n",
+		"hooks:\n  duringChaos:\n    echo: unknown phase\n",
+		"hooks:\n  preChaos:\n    unknown: hook\n",
+		"hooks:\n  preChaos:\n    echo: [malformed]\n",
+		"hooks:\n  preChaos:\n  - echo: valid\n  - unknown: hook\n",
+	} {
+		err = NewConfig().Parse([]byte(conf + hooks))
+		require.Error(t, err)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	executed := 0
+	hook := sequence([]func(context.Context) error{
+		func(context.Context) error {
+			executed++
+			return errors.New("first failed")
+		},
+		func(context.Context) error {
+			executed++
+			return nil
+		},
+		func(context.Context) error {
+			executed++
+			return errors.New("third failed")
+		},
+	})
+
+	err := hook(context.Background())
+	require.EqualError(t, err, "hook 1: first failed; hook 3: third failed")
+	require.Equal(t, 3, executed)
+}
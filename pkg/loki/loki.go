@@ -32,6 +32,9 @@ var (
 
 	readyParsers = make(map[string]func(*Config) ReadyParser)
 	readyMx      sync.Mutex
+
+	hookParsers = make(map[string]func(*Config) HookParser)
+	hooksMx     sync.Mutex
 )
 
 // ID uniquely represents any resource or operation in a system.
@@ -55,6 +58,29 @@ type System interface {
 	AsJSON(ctx context.Context, reload bool) ([]byte, error)
 }
 
+// Restorer is optionally implemented by System to bring resources back when system fails to recover by itself from a
+// chaos scenario, so that a failed scenario doesn't leave the system broken.
+type Restorer interface {
+	// Restore restores the resources lost in chaos scenario which killed given identifiers and returns the identifiers
+	// of resources which had to be restored.
+	Restore(context.Context, ...Identifier) (Identifiers, error)
+}
+
+// DriftReporter is optionally implemented by System to explain why the last validation found system out of desired
+// state.
+type DriftReporter interface {
+	// Drift returns the deviations from desired state found by the last call to Validate.
+	Drift() []Drift
+}
+
+// Resolver is optionally implemented by System whose destroy sections can refer to groups of resources, for example by
+// selectors, rather than individual resources. Identifiers of scenarios and exclusions are resolved into individual
+// resources once the system is loaded.
+type Resolver interface {
+	// Resolve resolves given identifiers into identifiers of individual resources of the system.
+	Resolve(context.Context, Identifiers) (Identifiers, error)
+}
+
 // Destroyer parses the single section of destroy whether it be exclusions or scenarios. Plugin implementations
 // need to implement this interface.
 type Destroyer interface {
@@ -117,12 +143,36 @@ func (r ReadyParserFunc) Parse(readyConf map[string]interface{}) (ReadyCond, err
 	return r(readyConf)
 }
 
+// HookParser parses a hook definition of hooks section to create the user defined action executed by the hook.
+type HookParser interface {
+	// Parse parses the hook definition and creates the action.
+	Parse(map[string]interface{}) (func(context.Context) error, error)
+}
+
+// HookParserFunc is the syntax sugar for single method HookParser interface so that a simple function can implement
+// HookParser interface.
+type HookParserFunc func(map[string]interface{}) (func(context.Context) error, error)
+
+// Parse calls h(hookConf).
+func (h HookParserFunc) Parse(hookConf map[string]interface{}) (func(context.Context) error, error) {
+	return h(hookConf)
+}
+
 // Identifier uniquely  identifies any resource or operation in a particular system.
 type Identifier interface {
 	// ID returns the unique identifier of resource or operation in a particular system.
 	ID() ID
 }
 
+// Metadata is optionally implemented by Identifier to describe the resource, so that random scenarios can select
+// resources by their tags and pick some of them more often than others.
+type Metadata interface {
+	// Tags returns the tags of resource, for example labels of kubernetes resource.
+	Tags() map[string]string
+	// Weight returns the relative likelihood of resource being picked by random scenarios. Weight of 0 is treated as 1.
+	Weight() float64
+}
+
 // Identifiers is group of Identifier which can be used to create scenarios for creating chaos, excluding the chaos
 // scenarios etc.
 type Identifiers []Identifier
@@ -174,3 +224,11 @@ func RegisterReadyParser(key string, parser func(*Config) ReadyParser) {
 
 	readyParsers[key] = parser
 }
+
+// RegisterHookParser registers HookParser creating functions that can be used by hooks section of config file.
+func RegisterHookParser(key string, parser func(*Config) HookParser) {
+	hooksMx.Lock()
+	defer hooksMx.Unlock()
+
+	hookParsers[key] = parser
+}
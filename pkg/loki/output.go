@@ -0,0 +1,66 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type outputKey struct{}
+
+// output collects the output recorded while a step of chaos execution, such as ready check or hook, is executed.
+type output struct {
+	mx      sync.Mutex
+	entries []string
+}
+
+// withOutput returns the context which collects output recorded by RecordOutput along with the collector.
+func withOutput(ctx context.Context) (context.Context, *output) {
+	collector := &output{}
+	return context.WithValue(ctx, outputKey{}, collector), collector
+}
+
+// RecordOutput records output worth being reported, such as stdout and stderr of a command, for the step of chaos
+// execution being executed with ctx. It can be used by ReadyCond and hooks. Output is dropped if the step doesn't
+// collect it.
+func RecordOutput(ctx context.Context, out string) {
+	collector, ok := ctx.Value(outputKey{}).(*output)
+	if !ok || out == "" {
+		return
+	}
+
+	collector.mx.Lock()
+	defer collector.mx.Unlock()
+
+	collector.entries = append(collector.entries, out)
+}
+
+// reset discards the output recorded so far.
+func (o *output) reset() {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	o.entries = nil
+}
+
+// String returns all the output recorded so far.
+func (o *output) String() string {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	return strings.Join(o.entries, "\n")
+}
@@ -0,0 +1,289 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Plan lists the chaos scenarios of all systems which would be executed by ChaosMaker.
+type Plan struct {
+	// Systems contains the plan of each system.
+	Systems []SystemPlan `json:"systems"`
+	// Compound contains the scenarios involving multiple systems, which are executed after scenarios of all systems.
+	Compound []PlannedCompoundScenario `json:"compound,omitempty"`
+}
+
+// SystemPlan lists the chaos scenarios of a single system.
+type SystemPlan struct {
+	// Name of the system as defined in configuration.
+	Name string `json:"name"`
+	// Seed used to generate random scenarios of the system.
+	Seed *int64 `json:"seed,omitempty"`
+	// Scenarios are the chaos scenarios in the order of execution.
+	Scenarios []PlannedScenario `json:"scenarios"`
+	// Soak describes random scenarios generated continuously after the scenarios listed, if soak is defined.
+	Soak *PlannedSoak `json:"soak,omitempty"`
+}
+
+// PlannedSoak summarizes the random scenarios of a soak, which are generated only while chaos is executed.
+type PlannedSoak struct {
+	// Duration is the time for which scenarios are injected.
+	Duration string `json:"duration"`
+	// Interval is the time between start of consecutive scenarios, mean time between them for poisson arrival.
+	Interval string `json:"interval"`
+	// Arrival is the distribution of time between consecutive scenarios, one of fixed or poisson.
+	Arrival string `json:"arrival"`
+	// ExpectedScenarios is the number of scenarios expected during soak if every scenario completes within interval.
+	ExpectedScenarios int64 `json:"expectedScenarios,omitempty"`
+	// MinResources is the minimum number of resources killed by a scenario.
+	MinResources int64 `json:"minResources"`
+	// MaxResources is the maximum number of resources killed by a scenario.
+	MaxResources int64 `json:"maxResources"`
+	// Timeout is the duration within which system should get back into desired state.
+	Timeout string `json:"timeout"`
+	// RecoveryObjective is the duration within which system is expected to get back into desired state.
+	RecoveryObjective string `json:"recoveryObjective,omitempty"`
+}
+
+// PlannedScenario represents a single chaos scenario in the plan.
+type PlannedScenario struct {
+	// Timeout is the duration within which system should get back into desired state.
+	Timeout string `json:"timeout"`
+	// RecoveryObjective is the duration within which system is expected to get back into desired state.
+	RecoveryObjective string `json:"recoveryObjective,omitempty"`
+	// Identifiers are IDs of resources which would be killed.
+	Identifiers []ID `json:"identifiers"`
+}
+
+// PlannedCompoundScenario represents a single chaos scenario involving multiple systems in the plan.
+type PlannedCompoundScenario struct {
+	// Timeout is the duration within which all systems should get back into desired state.
+	Timeout string `json:"timeout"`
+	// RecoveryObjective is the duration within which all systems are expected to get back into desired state.
+	RecoveryObjective string `json:"recoveryObjective,omitempty"`
+	// Systems contains the identifiers of each system which would be killed together.
+	Systems []PlannedTarget `json:"systems"`
+}
+
+// PlannedTarget contains the identifiers of a single system which would be killed by compound scenario.
+type PlannedTarget struct {
+	// Name of the system as defined in configuration.
+	Name string `json:"name"`
+	// Identifiers are IDs of resources which would be killed.
+	Identifiers []ID `json:"identifiers"`
+}
+
+// Plan loads all the systems and computes chaos scenarios, both pre-defined and randomly generated ones, without killing
+// anything. Scenarios executed by a subsequent CreateChaos with same ChaosMaker are exactly the ones in the plan, except
+// for scenarios of soak which are only summarized as they're generated during chaos execution.
+func (cm *ChaosMaker) Plan(ctx context.Context) (*Plan, error) {
+	for name, system := range cm.systems {
+		if err := system.Load(ctx); err != nil {
+			return nil, errors.Wrapf(err, "system '%s' failed to load", name)
+		}
+	}
+
+	systemNames := make([]string, 0, len(cm.scenarioProviders))
+	for systemName := range cm.scenarioProviders {
+		systemNames = append(systemNames, systemName)
+	}
+
+	sort.Strings(systemNames)
+
+	plan := &Plan{}
+
+	for _, systemName := range systemNames {
+		provider := cm.scenarioProviders[systemName]
+
+		scenarios, err := provider.pending(ctx, cm.systems[systemName])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate scenarios for system '%s'", systemName)
+		}
+
+		systemPlan := SystemPlan{
+			Name:      systemName,
+			Scenarios: make([]PlannedScenario, 0, len(scenarios)),
+		}
+
+		if provider.randomized() {
+			seed := provider.seed
+			systemPlan.Seed = &seed
+		}
+
+		if provider.soak != nil {
+			systemPlan.Soak = provider.plannedSoak()
+		}
+
+		for _, scenario := range scenarios {
+			plannedScenario := PlannedScenario{
+				Timeout: scenario.timeout.String(),
+			}
+
+			if scenario.recoveryObjective > 0 {
+				plannedScenario.RecoveryObjective = scenario.recoveryObjective.String()
+			}
+
+			for _, identifier := range scenario.identifiers {
+				plannedScenario.Identifiers = append(plannedScenario.Identifiers, identifier.ID())
+			}
+
+			systemPlan.Scenarios = append(systemPlan.Scenarios, plannedScenario)
+		}
+
+		plan.Systems = append(plan.Systems, systemPlan)
+	}
+
+	for _, chaosScenario := range cm.compoundScenarios {
+		parts, err := cm.resolveParts(ctx, chaosScenario)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate '%s' scenario", compoundKey)
+		}
+
+		plannedScenario := PlannedCompoundScenario{
+			Timeout: chaosScenario.timeout.String(),
+		}
+
+		if chaosScenario.recoveryObjective > 0 {
+			plannedScenario.RecoveryObjective = chaosScenario.recoveryObjective.String()
+		}
+
+		for _, part := range parts {
+			target := PlannedTarget{
+				Name: part.systemName,
+			}
+
+			for _, identifier := range part.identifiers {
+				target.Identifiers = append(target.Identifiers, identifier.ID())
+			}
+
+			plannedScenario.Systems = append(plannedScenario.Systems, target)
+		}
+
+		plan.Compound = append(plan.Compound, plannedScenario)
+	}
+
+	return plan, nil
+}
+
+// WriteJSON writes the json representation of plan into the writer passed.
+func (p *Plan) WriteJSON(writer io.Writer) error {
+	plan, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall plan")
+	}
+
+	if _, err := writer.Write(plan); err != nil {
+		return errors.Wrap(err, "failed to write plan")
+	}
+
+	return nil
+}
+
+// WriteText writes the human readable representation of plan into the writer passed.
+func (p *Plan) WriteText(writer io.Writer) error {
+	for _, systemPlan := range p.Systems {
+		header := fmt.Sprintf("system '%s'", systemPlan.Name)
+		if systemPlan.Seed != nil {
+			header = fmt.Sprintf("%s (seed %d)", header, *systemPlan.Seed)
+		}
+
+		if _, err := fmt.Fprintf(writer, "%s: %d scenario(s)\n", header, len(systemPlan.Scenarios)); err != nil {
+			return errors.Wrap(err, "failed to write plan")
+		}
+
+		for i, scenario := range systemPlan.Scenarios {
+			limits := fmt.Sprintf("timeout %s", scenario.Timeout)
+			if scenario.RecoveryObjective != "" {
+				limits = fmt.Sprintf("%s, recovery objective %s", limits, scenario.RecoveryObjective)
+			}
+
+			if _, err := fmt.Fprintf(writer, "  scenario %d (%s):\n", i+1, limits); err != nil {
+				return errors.Wrap(err, "failed to write plan")
+			}
+
+			for _, id := range scenario.Identifiers {
+				if _, err := fmt.Fprintf(writer, "    - %s\n", id); err != nil {
+					return errors.Wrap(err, "failed to write plan")
+				}
+			}
+		}
+
+		if systemPlan.Soak != nil {
+			if err := systemPlan.Soak.writeText(writer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(p.Compound) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(writer, "compound: %d scenario(s)\n", len(p.Compound)); err != nil {
+		return errors.Wrap(err, "failed to write plan")
+	}
+
+	for i, scenario := range p.Compound {
+		limits := fmt.Sprintf("timeout %s", scenario.Timeout)
+		if scenario.RecoveryObjective != "" {
+			limits = fmt.Sprintf("%s, recovery objective %s", limits, scenario.RecoveryObjective)
+		}
+
+		if _, err := fmt.Fprintf(writer, "  scenario %d (%s):\n", i+1, limits); err != nil {
+			return errors.Wrap(err, "failed to write plan")
+		}
+
+		for _, target := range scenario.Systems {
+			if _, err := fmt.Fprintf(writer, "    system '%s':\n", target.Name); err != nil {
+				return errors.Wrap(err, "failed to write plan")
+			}
+
+			for _, id := range target.Identifiers {
+				if _, err := fmt.Fprintf(writer, "      - %s\n", id); err != nil {
+					return errors.Wrap(err, "failed to write plan")
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *PlannedSoak) writeText(writer io.Writer) error {
+	limits := fmt.Sprintf("timeout %s", s.Timeout)
+	if s.RecoveryObjective != "" {
+		limits = fmt.Sprintf("%s, recovery objective %s", limits, s.RecoveryObjective)
+	}
+
+	expected := ""
+	if s.ExpectedScenarios > 0 {
+		expected = fmt.Sprintf(", about %d scenario(s)", s.ExpectedScenarios)
+	}
+
+	_, err := fmt.Fprintf(writer, "  soak for %s (%s arrival every %s%s) of %d-%d resource(s) (%s)\n", s.Duration,
+		s.Arrival, s.Interval, expected, s.MinResources, s.MaxResources, limits)
+	if err != nil {
+		return errors.Wrap(err, "failed to write plan")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	RegisterTestSystem(t)
+
+	conf, err := ioutil.ReadFile("../../testdata/sample-config.yaml")
+	require.NoError(t, err)
+
+	configuration := NewConfig()
+
+	err = configuration.Parse(conf)
+	require.NoError(t, err)
+
+	configuration.SetSeed(7)
+
+	chaosMaker := &ChaosMaker{
+		Config:      configuration,
+		FieldLogger: logrus.New(),
+	}
+
+	plan, err := chaosMaker.Plan(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(plan.Systems))
+
+	systemPlan := plan.Systems[0]
+	require.Equal(t, "testing", systemPlan.Name)
+	require.Equal(t, int64(7), *systemPlan.Seed)
+	require.Equal(t, 4, len(systemPlan.Scenarios))
+	require.Equal(t, []ID{"resource2"}, systemPlan.Scenarios[0].Identifiers)
+	require.Equal(t, "10m0s", systemPlan.Scenarios[0].Timeout)
+	require.Equal(t, "10s", systemPlan.Scenarios[2].Timeout)
+
+	system := configuration.systems["testing"].(*TestSystem)
+	require.Equal(t, len(system.Resources), len(system.State))
+
+	for _, plannedScenario := range systemPlan.Scenarios {
+		scenario, ok, err := configuration.scenarioProviders["testing"].scenario(context.Background(), system)
+		require.NoError(t, err)
+		require.Equal(t, true, ok)
+		require.Equal(t, len(plannedScenario.Identifiers), len(scenario.identifiers))
+
+		for i, identifier := range scenario.identifiers {
+			require.Equal(t, plannedScenario.Identifiers[i], identifier.ID())
+		}
+	}
+
+	text := &bytes.Buffer{}
+	err = plan.WriteText(text)
+	require.NoError(t, err)
+	require.Contains(t, text.String(), "system 'testing' (seed 7): 4 scenario(s)")
+
+	jsonPlan := &bytes.Buffer{}
+	err = plan.WriteJSON(jsonPlan)
+	require.NoError(t, err)
+	require.Contains(t, jsonPlan.String(), `"seed": 7`)
+}
+
+func TestPlanSoak(t *testing.T) {
+	RegisterTestSystem(t)
+
+	configuration := NewConfig()
+
+	err := configuration.Parse([]byte(fmt.Sprintf(soakConf, "      duration: 48h\n      interval: 10m")))
+	require.NoError(t, err)
+
+	chaosMaker := &ChaosMaker{
+		Config:      configuration,
+		FieldLogger: logrus.New(),
+	}
+
+	plan, err := chaosMaker.Plan(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(plan.Systems))
+
+	systemPlan := plan.Systems[0]
+	require.NotNil(t, systemPlan.Seed)
+	require.Equal(t, 1, len(systemPlan.Scenarios))
+	require.Equal(t, &PlannedSoak{
+		Duration:          "48h0m0s",
+		Interval:          "10m0s",
+		Arrival:           fixedArrival,
+		ExpectedScenarios: 288,
+		MinResources:      1,
+		MaxResources:      1,
+		Timeout:           "10m0s",
+	}, systemPlan.Soak)
+
+	text := &bytes.Buffer{}
+	err = plan.WriteText(text)
+	require.NoError(t, err)
+	require.Contains(t, text.String(), "soak for 48h0m0s (fixed arrival every 10m0s, about 288 scenario(s))")
+}
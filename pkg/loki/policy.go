@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RunPolicy determines whether chaos execution continues after a scenario fails.
+type RunPolicy string
+
+const (
+	// FailFast stops chaos execution on first scenario failure.
+	FailFast RunPolicy = "failFast"
+	// RunAll executes all scenarios irrespective of failures.
+	RunAll RunPolicy = "runAll"
+	// StopAfterFailures stops chaos execution once configured number of scenarios have failed.
+	StopAfterFailures RunPolicy = "stopAfterFailures"
+)
+
+// ParseRunPolicy converts string representation of run policy into RunPolicy.
+func ParseRunPolicy(policy string) (RunPolicy, error) {
+	switch RunPolicy(policy) {
+	case FailFast, RunAll, StopAfterFailures:
+		return RunPolicy(policy), nil
+	default:
+		return "", errors.Errorf("unidentified run policy '%s', should be one of '%s', '%s' or '%s'",
+			policy, FailFast, RunAll, StopAfterFailures)
+	}
+}
+
+// ScenarioFailures aggregates the errors of all scenarios which failed during chaos execution.
+type ScenarioFailures []error
+
+// Error returns the errors of all failed scenarios separated by new line.
+func (f ScenarioFailures) Error() string {
+	messages := make([]string, 0, len(f))
+
+	for _, err := range f {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
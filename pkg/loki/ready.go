@@ -16,18 +16,28 @@ package loki
 
 import (
 	"context"
+	"sort"
 	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/narahari92/loki/pkg/wait"
 )
 
 const (
 	afterKey    = "after"
 	allReadyKey = "allReady"
+	allOfKey    = "allOf"
+	anyOfKey    = "anyOf"
+	notKey      = "not"
 )
 
 // After is a very simple ReadyFunc which sleeps for given duration and then marks systems as ready.
 func After(duration time.Duration) ReadyFunc {
-	return func(context.Context) (bool, error) {
-		time.Sleep(duration)
+	return func(ctx context.Context) (bool, error) {
+		if err := wait.Sleep(ctx, duration); err != nil {
+			return false, err
+		}
 
 		return true, nil
 	}
@@ -65,3 +75,136 @@ func AllReady(readyConditions ...ReadyCond) ReadyFunc {
 		return true, nil
 	}
 }
+
+// AnyReady is a ReadyFunc which takes in multiple ReadyCond instances and marks systems as ready when any of the
+// ReadyCond is in ready state. Error of a ReadyCond is returned only if none of them is ready.
+func AnyReady(readyConditions ...ReadyCond) ReadyFunc {
+	return func(ctx context.Context) (bool, error) {
+		var firstErr error
+
+		for _, readyCond := range readyConditions {
+			ready, err := readyCond.Ready(ctx)
+			if err == nil && ready {
+				return true, nil
+			}
+
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		return false, firstErr
+	}
+}
+
+// NotReady is a ReadyFunc which marks systems as ready when the ReadyCond passed is not in ready state.
+func NotReady(readyCond ReadyCond) ReadyFunc {
+	return func(ctx context.Context) (bool, error) {
+		ready, err := readyCond.Ready(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		return !ready, nil
+	}
+}
+
+// parseCondition creates ReadyCond from a ready condition definition. Each of the combinators allOf, anyOf and not as
+// well as registered ready parsers whose key is present in the definition contributes a ReadyCond and all of them
+// must be ready together, evaluated in the order of their keys.
+func (c *Config) parseCondition(readyConf map[string]interface{}) (ReadyCond, error) {
+	keys := make([]string, 0, len(readyConf))
+	for key := range readyConf {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	readyConditions := make([]ReadyCond, 0, len(keys))
+
+	for _, key := range keys {
+		var (
+			readyCond ReadyCond
+			err       error
+		)
+
+		switch key {
+		case allOfKey:
+			readyCond, err = c.parseConditions(key, readyConf[key], AllReady)
+		case anyOfKey:
+			readyCond, err = c.parseConditions(key, readyConf[key], AnyReady)
+		case notKey:
+			readyCond, err = c.parseNot(readyConf[key])
+		default:
+			parser, ok := readyParsers[key]
+			if !ok {
+				continue
+			}
+
+			readyCond, err = parser(c).Parse(readyConf)
+			if err != nil {
+				err = errors.Wrapf(err, "failed to parse using '%s' ready parser", key)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		readyConditions = append(readyConditions, readyCond)
+	}
+
+	switch len(readyConditions) {
+	case 0:
+		return nil, errors.Errorf("no ready condition defined in %v", readyConf)
+	case 1:
+		return readyConditions[0], nil
+	default:
+		return AllReady(readyConditions...), nil
+	}
+}
+
+// parseConditions parses the array of ready conditions of a combinator and combines them using combine.
+func (c *Config) parseConditions(
+	key string,
+	conditions interface{},
+	combine func(...ReadyCond) ReadyFunc,
+) (ReadyCond, error) {
+	conditionConfs, ok := conditions.([]interface{})
+	if !ok || len(conditionConfs) == 0 {
+		return nil, errors.Errorf("'%s' field should be of type non-empty array", key)
+	}
+
+	readyConditions := make([]ReadyCond, 0, len(conditionConfs))
+
+	for i, conditionConf := range conditionConfs {
+		readyConf, ok := conditionConf.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("condition %d of '%s' should be of type map", i+1, key)
+		}
+
+		readyCond, err := c.parseCondition(readyConf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse condition %d of '%s'", i+1, key)
+		}
+
+		readyConditions = append(readyConditions, readyCond)
+	}
+
+	return combine(readyConditions...), nil
+}
+
+// parseNot parses the ready condition negated by not combinator.
+func (c *Config) parseNot(condition interface{}) (ReadyCond, error) {
+	readyConf, ok := condition.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("'%s' field should be of type map", notKey)
+	}
+
+	readyCond, err := c.parseCondition(readyConf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse condition of '%s'", notKey)
+	}
+
+	return NotReady(readyCond), nil
+}
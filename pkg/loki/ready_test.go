@@ -19,6 +19,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,3 +47,113 @@ func TestAllReady(t *testing.T) {
 	require.Equal(t, true, ready)
 	require.Equal(t, int64(6), timeDiff/(1000*1000*1000))
 }
+
+func TestAnyReady(t *testing.T) {
+	notReady := ReadyFunc(func(context.Context) (bool, error) {
+		return false, nil
+	})
+	failed := ReadyFunc(func(context.Context) (bool, error) {
+		return false, errors.New("failed")
+	})
+
+	ready, err := AnyReady(notReady, failed, After(0))(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, ready)
+
+	ready, err = AnyReady(notReady, failed)(context.Background())
+	require.Error(t, err)
+	require.Equal(t, false, ready)
+
+	ready, err = NotReady(notReady)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, true, ready)
+}
+
+func TestParseCondition(t *testing.T) {
+	RegisterReadyParser("toggle", func(*Config) ReadyParser {
+		return ReadyParserFunc(func(readyConf map[string]interface{}) (ReadyCond, error) {
+			ready, ok := readyConf["toggle"].(bool)
+			if !ok {
+				return nil, errors.New("'toggle' field should be of type bool")
+			}
+
+			return ReadyFunc(func(context.Context) (bool, error) {
+				return ready, nil
+			}), nil
+		})
+	})
+
+	tests := []struct {
+		description string
+		conf        string
+		ready       bool
+		expectedErr bool
+	}{
+		{
+			description: "single condition",
+			conf:        "toggle: true",
+			ready:       true,
+		},
+		{
+			description: "multiple keys are combined",
+			conf:        "toggle: true\nnot:\n  toggle: true",
+			ready:       false,
+		},
+		{
+			description: "all of conditions",
+			conf:        "allOf:\n- toggle: true\n- after: 0s",
+			ready:       true,
+		},
+		{
+			description: "any of conditions",
+			conf:        "anyOf:\n- toggle: false\n- allOf:\n  - toggle: true\n  - after: 0s",
+			ready:       true,
+		},
+		{
+			description: "none of any of conditions",
+			conf:        "anyOf:\n- toggle: false\n- not:\n    toggle: true",
+			ready:       false,
+		},
+		{
+			description: "empty all of",
+			conf:        "allOf: []",
+			expectedErr: true,
+		},
+		{
+			description: "malformed not",
+			conf:        "not:\n- toggle: true",
+			expectedErr: true,
+		},
+		{
+			description: "nested failure",
+			conf:        "anyOf:\n- toggle: yes please",
+			expectedErr: true,
+		},
+		{
+			description: "unidentified condition",
+			conf:        "allOf:\n- unknown: true",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			readyConf := make(map[string]interface{})
+			err := yaml.Unmarshal([]byte(test.conf), &readyConf)
+			require.NoError(t, err)
+
+			readyCond, err := NewConfig().parseCondition(readyConf)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			ready, err := readyCond.Ready(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, test.ready, ready)
+		})
+	}
+}
@@ -15,6 +15,7 @@ limitations under the License.
 package loki
 
 import (
+	"context"
 	"hash/fnv"
 	"math/rand"
 	"sort"
@@ -26,95 +27,321 @@ import (
 	"github.com/pkg/errors"
 )
 
-const maxTotalClashes = 10
+const (
+	maxTotalClashes = 10
+	// defaultMaxResources is the maximum number of resources killed by a random scenario when it isn't configured.
+	defaultMaxResources = 5
+	// maxSeed keeps generated seeds within the integer precision of float64 so that they can be provided back through
+	// configuration without loss.
+	maxSeed = 1 << 53
+)
 
 type scenario struct {
-	timeout     time.Duration
-	identifiers Identifiers
+	timeout           time.Duration
+	recoveryObjective time.Duration
+	identifiers       Identifiers
 }
 
 type scenarioProvider struct {
-	exclusions          []Identifiers
+	exclusions          []*exclusion
 	predefinedScenarios []*scenario
+	combinations        []*combinations
 	randomTimeout       time.Duration
+	randomObjective     time.Duration
 	random              int64
+	soak                *soak
 	minResources        int64
 	maxResources        int64
+	seed                int64
+	rng                 *rand.Rand
+	selection           *selection
+	allIdentifiers      Identifiers
+	weights             []float64
+	next                time.Time
+	deadline            time.Time
 	computed            sync.Once
+	computeErr          error
 	computedScenarios   []*scenario
 }
 
-func (sp *scenarioProvider) scenario(system System) (*scenario, bool, error) {
-	var err error
+func (sp *scenarioProvider) scenario(ctx context.Context, system System) (*scenario, bool, error) {
+	if err := sp.compute(ctx, system); err != nil {
+		return nil, false, err
+	}
+
+	if len(sp.computedScenarios) > 0 {
+		scenario := sp.computedScenarios[0]
+		sp.computedScenarios = sp.computedScenarios[1:len(sp.computedScenarios)]
+
+		return scenario, true, nil
+	}
+
+	if sp.soak != nil {
+		return sp.soakScenario(ctx)
+	}
+
+	return nil, false, nil
+}
+
+// randomized returns whether random scenarios are generated, either a fixed number of them or for soak duration.
+func (sp *scenarioProvider) randomized() bool {
+	return sp.random > 0 || sp.soak != nil
+}
+
+// pending returns the scenarios which are yet to be provided by scenario method without consuming them.
+func (sp *scenarioProvider) pending(ctx context.Context, system System) ([]*scenario, error) {
+	if err := sp.compute(ctx, system); err != nil {
+		return nil, err
+	}
+
+	scenarios := make([]*scenario, len(sp.computedScenarios))
+	copy(scenarios, sp.computedScenarios)
 
+	return scenarios, nil
+}
+
+func (sp *scenarioProvider) compute(ctx context.Context, system System) error {
 	sp.computed.Do(func() {
-		sp.computedScenarios = append(sp.computedScenarios, sp.predefinedScenarios...)
+		sp.computeErr = sp.generate(ctx, system)
+	})
 
-		if sp.random <= 0 {
-			return
-		}
+	return sp.computeErr
+}
+
+func (sp *scenarioProvider) generate(ctx context.Context, system System) error {
+	if err := sp.resolve(ctx, system); err != nil {
+		return err
+	}
 
-		totalClashes := 0
-		exclusionHashes := generateExclusionHashes(sp.exclusions)
+	sp.computedScenarios = append(sp.computedScenarios, sp.predefinedScenarios...)
 
-		for _, predefinedScenario := range sp.predefinedScenarios {
-			exclusionHashes = append(exclusionHashes, generateIdentifiersHash(predefinedScenario.identifiers))
-		}
+	if !sp.randomized() && len(sp.combinations) == 0 {
+		return nil
+	}
 
-		allIdentifiers := system.Identifiers()
+	sp.allIdentifiers = sp.killableIdentifiers(system)
 
-		for i := int64(0); i < sp.random; i++ {
-			if totalClashes == maxTotalClashes {
-				err = errors.New("too many clashes with exclusions")
-				return
-			}
-			noOfIdentifiers := rand.Int63n(sp.maxResources-sp.minResources) + sp.minResources
+	var scenarioHashes []string
 
-			var identifiers Identifiers
+	for _, predefinedScenario := range sp.predefinedScenarios {
+		scenarioHashes = append(scenarioHashes, generateIdentifiersHash(predefinedScenario.identifiers))
+	}
 
-			for j := int64(0); j < noOfIdentifiers; j++ {
-				identIdx := rand.Intn(len(allIdentifiers))
-				identifiers = append(identifiers, allIdentifiers[identIdx])
-			}
+	// scenarios of combinations which are already executed by an earlier scenario are skipped.
+	for _, chaosCombinations := range sp.combinations {
+		combinedIdentifiers, err := chaosCombinations.generate(sp.allIdentifiers, sp.excluded)
+		if err != nil {
+			return err
+		}
 
+		for _, identifiers := range combinedIdentifiers {
 			identifierHash := generateIdentifiersHash(identifiers)
-			if exists(exclusionHashes, identifierHash) {
-				i--
-				totalClashes++
+			if exists(scenarioHashes, identifierHash) {
 				continue
 			}
 
-			exclusionHashes = append(exclusionHashes, generateIdentifiersHash(identifiers))
+			scenarioHashes = append(scenarioHashes, identifierHash)
 			sp.computedScenarios = append(sp.computedScenarios, &scenario{
-				timeout:     sp.randomTimeout,
-				identifiers: identifiers,
+				timeout:           chaosCombinations.timeout,
+				recoveryObjective: chaosCombinations.recoveryObjective,
+				identifiers:       identifiers,
 			})
 		}
+	}
+
+	if !sp.randomized() {
+		return nil
+	}
+
+	sp.rng = rand.New(rand.NewSource(sp.seed))
+	sp.allIdentifiers = sp.selection.filter(sp.allIdentifiers)
+	sp.weights = sp.selection.identifierWeights(sp.allIdentifiers)
+
+	if err := sp.checkBounds(); err != nil {
+		return err
+	}
+
+	// scenarios of soak are generated as they're due.
+	if sp.soak != nil {
+		return nil
+	}
+
+	totalClashes := 0
+
+	for i := int64(0); i < sp.random; i++ {
+		if totalClashes == maxTotalClashes {
+			return errors.New("too many clashes with exclusions")
+		}
+
+		identifiers := sp.randomIdentifiers()
+
+		identifierHash := generateIdentifiersHash(identifiers)
+		if sp.excluded(identifiers) || exists(scenarioHashes, identifierHash) {
+			i--
+			totalClashes++
+			continue
+		}
+
+		scenarioHashes = append(scenarioHashes, identifierHash)
+		sp.computedScenarios = append(sp.computedScenarios, &scenario{
+			timeout:           sp.randomTimeout,
+			recoveryObjective: sp.randomObjective,
+			identifiers:       identifiers,
+		})
+	}
+
+	return nil
+}
+
+// killableIdentifiers returns the identifiers of system which can be killed by generated scenarios, leaving out the ones
+// which exclusions never allow to be killed. Identifiers are sorted so that same seed always generates same scenarios.
+func (sp *scenarioProvider) killableIdentifiers(system System) Identifiers {
+	var identifiers Identifiers
+
+	for _, identifier := range system.Identifiers() {
+		if !sp.excluded(Identifiers{identifier}) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+
+	sort.Slice(identifiers, func(i, j int) bool {
+		return identifiers[i].ID() < identifiers[j].ID()
 	})
 
-	if err != nil {
-		return nil, false, err
+	return identifiers
+}
+
+// checkBounds makes sure that random scenarios can kill from minimum resources up to, but excluding, maximum resources
+// from the identifiers of system. Same minimum and maximum kill exactly that many resources. Maximum defaults to
+// defaultMaxResources limited by the identifiers available.
+func (sp *scenarioProvider) checkBounds() error {
+	available := int64(len(sp.allIdentifiers))
+
+	if sp.minResources > available {
+		return errors.Errorf("'%s' %d is more than %d killable resource(s) in system", minResourcesKey,
+			sp.minResources, available)
 	}
 
-	if len(sp.computedScenarios) > 0 {
-		scenario := sp.computedScenarios[0]
-		sp.computedScenarios = sp.computedScenarios[1:len(sp.computedScenarios)]
+	if sp.maxResources == 0 {
+		sp.maxResources = defaultMaxResources
+		if sp.maxResources > available+1 {
+			sp.maxResources = available + 1
+		}
 
-		return scenario, true, nil
+		if sp.maxResources < sp.minResources {
+			sp.maxResources = sp.minResources
+		}
 	}
 
-	return nil, false, nil
+	if sp.minResources < 1 || sp.maxResources < sp.minResources {
+		return errors.Errorf("invalid bounds of random scenario, '%s' %d and '%s' %d", minResourcesKey,
+			sp.minResources, maxResourcesKey, sp.maxResources)
+	}
+
+	if largest := sp.largestResources(); largest > available {
+		return errors.Errorf("'%s' %d allows killing %d resources, more than %d killable resource(s) in system",
+			maxResourcesKey, sp.maxResources, largest, available)
+	}
+
+	return nil
 }
 
-func generateExclusionHashes(exclusions []Identifiers) []string {
-	var exclusionHashes []string
+// largestResources returns the largest number of resources killed by a random scenario, as maximum resources is
+// exclusive unless it's same as minimum resources.
+func (sp *scenarioProvider) largestResources() int64 {
+	if sp.maxResources > sp.minResources {
+		return sp.maxResources - 1
+	}
+
+	return sp.minResources
+}
+
+// randomIdentifiers picks distinct random identifiers of system for a single scenario by partially shuffling them with
+// Fisher-Yates algorithm. Number of identifiers is at least minimum resources and less than maximum resources, or
+// exactly minimum resources if both are same. Identifiers with weights are picked with probability proportional to their
+// weight among the ones not picked yet.
+func (sp *scenarioProvider) randomIdentifiers() Identifiers {
+	noOfIdentifiers := sp.minResources + sp.rng.Int63n(sp.largestResources()-sp.minResources+1)
+
+	pool := make(Identifiers, len(sp.allIdentifiers))
+	copy(pool, sp.allIdentifiers)
+
+	if sp.weights == nil {
+		for i := int64(0); i < noOfIdentifiers; i++ {
+			j := i + sp.rng.Int63n(int64(len(pool))-i)
+			pool[i], pool[j] = pool[j], pool[i]
+		}
 
-	for _, identifiers := range exclusions {
-		hash := generateIdentifiersHash(identifiers)
-		exclusionHashes = append(exclusionHashes, hash)
+		return pool[:noOfIdentifiers]
 	}
 
-	return exclusionHashes
+	weights := make([]float64, len(sp.weights))
+	copy(weights, sp.weights)
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	for i := int64(0); i < noOfIdentifiers; i++ {
+		target := sp.rng.Float64() * total
+
+		// last identifier is picked if rounding errors leave target beyond total weight of the rest.
+		j := i
+		for ; j < int64(len(pool))-1; j++ {
+			target -= weights[j]
+			if target < 0 {
+				break
+			}
+		}
+
+		pool[i], pool[j] = pool[j], pool[i]
+		weights[i], weights[j] = weights[j], weights[i]
+		total -= weights[i]
+	}
+
+	return pool[:noOfIdentifiers]
+}
+
+// resolve resolves the identifiers of pre-defined scenarios and exclusions into individual resources if system supports
+// resolution.
+func (sp *scenarioProvider) resolve(ctx context.Context, system System) error {
+	resolver, ok := system.(Resolver)
+	if !ok {
+		return nil
+	}
+
+	for i, predefinedScenario := range sp.predefinedScenarios {
+		identifiers, err := resolver.Resolve(ctx, predefinedScenario.identifiers)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve identifiers of scenario:\n%s", predefinedScenario.identifiers)
+		}
+
+		if len(identifiers) == 0 {
+			return errors.Errorf("scenario doesn't match any resource of system:\n%s", predefinedScenario.identifiers)
+		}
+
+		resolved := *predefinedScenario
+		resolved.identifiers = identifiers
+		sp.predefinedScenarios[i] = &resolved
+	}
+
+	for i, exclusion := range sp.exclusions {
+		identifiers, err := resolver.Resolve(ctx, exclusion.identifiers)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve identifiers of exclusion:\n%s", exclusion.identifiers)
+		}
+
+		resolved := *exclusion
+		resolved.identifiers = identifiers
+		sp.exclusions[i] = &resolved
+	}
+
+	return nil
+}
+
+func newSeed() int64 {
+	return time.Now().UnixNano() % maxSeed
 }
 
 func generateIdentifiersHash(identifiers Identifiers) string {
@@ -15,8 +15,12 @@ limitations under the License.
 package loki
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"testing"
+	"testing/quick"
 
 	"github.com/stretchr/testify/require"
 )
@@ -39,7 +43,7 @@ func TestScenario(t *testing.T) {
 		system := configuration.systems[systemName]
 
 		for {
-			scenario, ok, err := provider.scenario(system)
+			scenario, ok, err := provider.scenario(context.Background(), system)
 			require.NoError(t, err)
 
 			if !ok {
@@ -69,3 +73,256 @@ func TestScenario(t *testing.T) {
 	require.NotEqual(t, Identifiers{TestIdentifier("resource3"), TestIdentifier("resource2")}, scenarios[2])
 	require.NotEqual(t, scenarios[2], scenarios[3])
 }
+
+func TestScenarioSeed(t *testing.T) {
+	RegisterTestSystem(t)
+
+	conf, err := ioutil.ReadFile("../../testdata/sample-config.yaml")
+	require.NoError(t, err)
+
+	generateScenarios := func(seed int64) []Identifiers {
+		configuration := NewConfig()
+
+		err := configuration.Parse(conf)
+		require.NoError(t, err)
+
+		configuration.SetSeed(seed)
+
+		provider := configuration.scenarioProviders["testing"]
+		require.Equal(t, seed, provider.seed)
+
+		scenarios := make([]Identifiers, 0)
+
+		for {
+			scenario, ok, err := provider.scenario(context.Background(), configuration.systems["testing"])
+			require.NoError(t, err)
+
+			if !ok {
+				break
+			}
+
+			scenarios = append(scenarios, scenario.identifiers)
+		}
+
+		return scenarios
+	}
+
+	require.Equal(t, generateScenarios(42), generateScenarios(42))
+}
+
+type resolvingSystem struct {
+	*TestSystem
+}
+
+func (r *resolvingSystem) Resolve(_ context.Context, identifiers Identifiers) (Identifiers, error) {
+	var resolved Identifiers
+
+	for _, identifier := range identifiers {
+		if identifier.ID() == "even" {
+			resolved = append(resolved, TestIdentifier("resource2"), TestIdentifier("resource4"))
+			continue
+		}
+
+		resolved = append(resolved, identifier)
+	}
+
+	return resolved, nil
+}
+
+func TestScenarioResolve(t *testing.T) {
+	provider := &scenarioProvider{
+		exclusions: []*exclusion{{identifiers: Identifiers{TestIdentifier("even")}}},
+		predefinedScenarios: []*scenario{
+			{identifiers: Identifiers{TestIdentifier("even"), TestIdentifier("resource1")}},
+		},
+	}
+
+	system := &resolvingSystem{
+		TestSystem: &TestSystem{
+			Resources: map[TestIdentifier]bool{"resource1": true, "resource2": true, "resource4": true},
+		},
+	}
+
+	scenario, ok, err := provider.scenario(context.Background(), system)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+	require.Equal(
+		t,
+		Identifiers{TestIdentifier("resource2"), TestIdentifier("resource4"), TestIdentifier("resource1")},
+		scenario.identifiers,
+	)
+	require.Equal(t, Identifiers{TestIdentifier("resource2"), TestIdentifier("resource4")}, provider.exclusions[0].identifiers)
+}
+
+func TestScenarioBounds(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		bounds      string
+		minimum     int64
+		maximum     int64
+		expectedErr bool
+	}{
+		{
+			description: "default bounds limited by resources of system",
+			minimum:     1,
+			maximum:     4,
+		},
+		{
+			description: "same minimum and maximum",
+			bounds:      "    minResources: 2\n    maxResources: 2\n",
+			minimum:     2,
+			maximum:     2,
+		},
+		{
+			description: "all resources of system",
+			bounds:      "    minResources: 3\n    maxResources: 3\n",
+			minimum:     3,
+			maximum:     3,
+		},
+		{
+			description: "minimum less than 1",
+			bounds:      "    minResources: 0\n",
+			expectedErr: true,
+		},
+		{
+			description: "maximum less than minimum",
+			bounds:      "    minResources: 2\n    maxResources: 1\n",
+			expectedErr: true,
+		},
+		{
+			description: "exclusive maximum beyond resources of system",
+			bounds:      "    maxResources: 4\n",
+			minimum:     1,
+			maximum:     4,
+		},
+		{
+			description: "maximum more than resources of system",
+			bounds:      "    maxResources: 5\n",
+			expectedErr: true,
+		},
+		{
+			description: "minimum more than resources of system",
+			bounds:      "    minResources: 4\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			conf := fmt.Sprintf(`
+ready:
+  after: 0s
+systems:
+- type: test-system
+  name: testing
+  resources:
+  - resource1
+  - resource2
+  - resource3
+destroy:
+  scenarios:
+  - system: testing
+    random: 1
+%s`, test.bounds)
+
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(conf))
+			if err == nil {
+				_, err = configuration.scenarioProviders["testing"].pending(
+					context.Background(), configuration.systems["testing"])
+			}
+
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			provider := configuration.scenarioProviders["testing"]
+			require.Equal(t, test.minimum, provider.minResources)
+			require.Equal(t, test.maximum, provider.maxResources)
+		})
+	}
+}
+
+func TestRandomIdentifiers(t *testing.T) {
+	// property: every random scenario kills distinct identifiers of system, at least minimum and fewer than maximum of
+	// them, or exactly minimum if maximum is same.
+	property := func(seed int64, size, minimum, spread uint8) bool {
+		available := int64(size%20) + 1
+		minResources := int64(minimum)%available + 1
+		maxResources := minResources + int64(spread)%(available-minResources+2)
+
+		provider := &scenarioProvider{
+			minResources: minResources,
+			maxResources: maxResources,
+			rng:          rand.New(rand.NewSource(seed)),
+		}
+
+		pool := make(map[Identifier]bool)
+
+		for i := int64(0); i < available; i++ {
+			identifier := TestIdentifier(fmt.Sprintf("resource%d", i))
+			provider.allIdentifiers = append(provider.allIdentifiers, identifier)
+			pool[identifier] = true
+		}
+
+		if err := provider.checkBounds(); err != nil {
+			return false
+		}
+
+		for i := 0; i < 10; i++ {
+			identifiers := provider.randomIdentifiers()
+			if int64(len(identifiers)) < minResources {
+				return false
+			}
+
+			if maxResources > minResources && int64(len(identifiers)) >= maxResources {
+				return false
+			}
+
+			if maxResources == minResources && int64(len(identifiers)) != minResources {
+				return false
+			}
+
+			seen := make(map[Identifier]bool)
+
+			for _, identifier := range identifiers {
+				if seen[identifier] || !pool[identifier] {
+					return false
+				}
+
+				seen[identifier] = true
+			}
+		}
+
+		return len(provider.allIdentifiers) == int(available)
+	}
+
+	require.NoError(t, quick.Check(property, nil))
+
+	// property: bounds beyond identifiers of system are rejected instead of generating scenarios.
+	bounds := func(size, minimum, maximum uint8) bool {
+		provider := &scenarioProvider{
+			minResources:   int64(minimum%10) + 1,
+			maxResources:   int64(maximum % 10),
+			allIdentifiers: make(Identifiers, size%10),
+		}
+
+		available := int64(size % 10)
+		configuredMax := provider.maxResources
+		err := provider.checkBounds()
+
+		valid := provider.minResources <= available &&
+			(configuredMax == 0 || (configuredMax >= provider.minResources && configuredMax <= available+1))
+
+		return valid == (err == nil)
+	}
+
+	require.NoError(t, quick.Check(bounds, nil))
+}
@@ -0,0 +1,212 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	includeKey = "include"
+	excludeKey = "exclude"
+	weightsKey = "weights"
+	tagsKey    = "tags"
+	weightKey  = "weight"
+)
+
+// selection restricts and biases the identifiers picked by random scenarios based on the Metadata of identifiers.
+type selection struct {
+	// include keeps only the identifiers having all of these tags, if defined.
+	include map[string]string
+	// exclude leaves out the identifiers having all of these tags, if defined.
+	exclude map[string]string
+	// weights override the weight of identifiers matching their tags. First matching weight is used.
+	weights []*tagWeight
+}
+
+// tagWeight is the weight of identifiers having all of the tags.
+type tagWeight struct {
+	tags   map[string]string
+	weight float64
+}
+
+// parseSelection parses the tag filters and weights of random scenario. It returns nil if none of them are defined.
+func parseSelection(scenario map[string]interface{}) (*selection, error) {
+	chaosSelection := &selection{}
+
+	var err error
+
+	if includeValue, ok := scenario[includeKey]; ok {
+		if chaosSelection.include, err = parseTags(includeKey, includeValue); err != nil {
+			return nil, err
+		}
+	}
+
+	if excludeValue, ok := scenario[excludeKey]; ok {
+		if chaosSelection.exclude, err = parseTags(excludeKey, excludeValue); err != nil {
+			return nil, err
+		}
+	}
+
+	if weightsValue, ok := scenario[weightsKey]; ok {
+		weightConfigs, ok := weightsValue.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("'%s' field should be of type array", weightsKey)
+		}
+
+		for _, weightConf := range weightConfigs {
+			weightSection, ok := weightConf.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("malformed weight configuration %v", weightConf)
+			}
+
+			tagsValue, ok := weightSection[tagsKey]
+			if !ok {
+				return nil, errors.Errorf("'%s' field is mandatory in '%s'", tagsKey, weightsKey)
+			}
+
+			tags, err := parseTags(tagsKey, tagsValue)
+			if err != nil {
+				return nil, err
+			}
+
+			weightValue, ok := weightSection[weightKey]
+			if !ok {
+				return nil, errors.Errorf("'%s' field is mandatory in '%s'", weightKey, weightsKey)
+			}
+
+			weight, ok := weightValue.(float64)
+			if !ok {
+				return nil, errors.Errorf("'%s' field should be of type number", weightKey)
+			}
+
+			if weight <= 0 {
+				return nil, errors.Errorf("'%s' should be greater than 0", weightKey)
+			}
+
+			chaosSelection.weights = append(chaosSelection.weights, &tagWeight{tags: tags, weight: weight})
+		}
+	}
+
+	if chaosSelection.include == nil && chaosSelection.exclude == nil && chaosSelection.weights == nil {
+		return nil, nil
+	}
+
+	return chaosSelection, nil
+}
+
+func parseTags(fieldName string, value interface{}) (map[string]string, error) {
+	tagsConf, ok := value.(map[string]interface{})
+	if !ok || len(tagsConf) == 0 {
+		return nil, errors.Errorf("'%s' field should be a non empty map", fieldName)
+	}
+
+	tags := make(map[string]string, len(tagsConf))
+
+	for key, tagValue := range tagsConf {
+		tag, ok := tagValue.(string)
+		if !ok {
+			return nil, errors.Errorf("tag '%s' of '%s' field should be of type string", key, fieldName)
+		}
+
+		tags[key] = tag
+	}
+
+	return tags, nil
+}
+
+// filter returns the identifiers which are included and not excluded by tags of selection.
+func (s *selection) filter(identifiers Identifiers) Identifiers {
+	if s == nil {
+		return identifiers
+	}
+
+	var selected Identifiers
+
+	for _, identifier := range identifiers {
+		tags := identifierTags(identifier)
+
+		if s.include != nil && !hasTags(tags, s.include) {
+			continue
+		}
+
+		if s.exclude != nil && hasTags(tags, s.exclude) {
+			continue
+		}
+
+		selected = append(selected, identifier)
+	}
+
+	return selected
+}
+
+// identifierWeights returns the weight of each of the identifiers, or nil if all of them are equally likely to be picked.
+func (s *selection) identifierWeights(identifiers Identifiers) []float64 {
+	weights := make([]float64, 0, len(identifiers))
+	uniform := true
+
+	for _, identifier := range identifiers {
+		weight := s.weight(identifier)
+		if len(weights) > 0 && weight != weights[0] {
+			uniform = false
+		}
+
+		weights = append(weights, weight)
+	}
+
+	if uniform {
+		return nil
+	}
+
+	return weights
+}
+
+// weight returns the first weight of selection matching tags of identifier, or else the weight of identifier itself.
+func (s *selection) weight(identifier Identifier) float64 {
+	if s != nil {
+		tags := identifierTags(identifier)
+
+		for _, rule := range s.weights {
+			if hasTags(tags, rule.tags) {
+				return rule.weight
+			}
+		}
+	}
+
+	if metadata, ok := identifier.(Metadata); ok && metadata.Weight() > 0 {
+		return metadata.Weight()
+	}
+
+	return 1
+}
+
+func identifierTags(identifier Identifier) map[string]string {
+	if metadata, ok := identifier.(Metadata); ok {
+		return metadata.Tags()
+	}
+
+	return nil
+}
+
+// hasTags returns whether tags contain all the expected tags.
+func hasTags(tags, expected map[string]string) bool {
+	for key, value := range expected {
+		if tag, ok := tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+
+	return true
+}
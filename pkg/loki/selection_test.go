@@ -0,0 +1,188 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const selectionConf = `
+ready:
+  after: 0s
+systems:
+- type: test-system
+  name: testing
+  resources:
+  - resource1
+destroy:
+  scenarios:
+  - system: testing
+    random: 1
+%s
+`
+
+type taggedIdentifier struct {
+	TestIdentifier
+	tags   map[string]string
+	weight float64
+}
+
+func (t *taggedIdentifier) Tags() map[string]string {
+	return t.tags
+}
+
+func (t *taggedIdentifier) Weight() float64 {
+	return t.weight
+}
+
+func TestParseSelection(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		selection   string
+		expected    *selection
+		expectedErr bool
+	}{
+		{
+			description: "no selection",
+		},
+		{
+			description: "include and exclude",
+			selection:   "    include:\n      tier: frontend\n    exclude:\n      zone: a",
+			expected: &selection{
+				include: map[string]string{"tier": "frontend"},
+				exclude: map[string]string{"zone": "a"},
+			},
+		},
+		{
+			description: "weights",
+			selection: "    weights:\n    - tags:\n        tier: frontend\n      weight: 4\n" +
+				"    - tags:\n        tier: db\n      weight: 0.5",
+			expected: &selection{
+				weights: []*tagWeight{
+					{tags: map[string]string{"tier": "frontend"}, weight: 4},
+					{tags: map[string]string{"tier": "db"}, weight: 0.5},
+				},
+			},
+		},
+		{
+			description: "empty include",
+			selection:   "    include: {}",
+			expectedErr: true,
+		},
+		{
+			description: "non string tag",
+			selection:   "    exclude:\n      replicas: 1",
+			expectedErr: true,
+		},
+		{
+			description: "weight without tags",
+			selection:   "    weights:\n    - weight: 2",
+			expectedErr: true,
+		},
+		{
+			description: "weight not greater than 0",
+			selection:   "    weights:\n    - tags:\n        tier: frontend\n      weight: 0",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(fmt.Sprintf(selectionConf, test.selection)))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, configuration.scenarioProviders["testing"].selection)
+		})
+	}
+}
+
+func TestSelection(t *testing.T) {
+	frontend := &taggedIdentifier{TestIdentifier: "frontend", tags: map[string]string{"tier": "frontend", "zone": "a"}}
+	backend := &taggedIdentifier{TestIdentifier: "backend", tags: map[string]string{"tier": "backend"}, weight: 3}
+	database := &taggedIdentifier{TestIdentifier: "database", tags: map[string]string{"tier": "database"}}
+	untagged := TestIdentifier("untagged")
+	identifiers := Identifiers{frontend, backend, database, untagged}
+
+	var noSelection *selection
+	require.Equal(t, identifiers, noSelection.filter(identifiers))
+	require.Equal(t, []float64{1, 3, 1, 1}, noSelection.identifierWeights(identifiers))
+
+	chaosSelection := &selection{include: map[string]string{"tier": "frontend"}}
+	require.Equal(t, Identifiers{frontend}, chaosSelection.filter(identifiers))
+
+	chaosSelection = &selection{exclude: map[string]string{"tier": "database"}}
+	require.Equal(t, Identifiers{frontend, backend, untagged}, chaosSelection.filter(identifiers))
+
+	chaosSelection = &selection{
+		weights: []*tagWeight{
+			{tags: map[string]string{"tier": "frontend", "zone": "a"}, weight: 5},
+			{tags: map[string]string{"tier": "frontend"}, weight: 2},
+			{tags: map[string]string{"tier": "backend"}, weight: 0.5},
+		},
+	}
+	require.Equal(t, []float64{5, 0.5, 1, 1}, chaosSelection.identifierWeights(identifiers))
+
+	chaosSelection = &selection{weights: []*tagWeight{{tags: map[string]string{"tier": "backend"}, weight: 1}}}
+	require.Nil(t, chaosSelection.identifierWeights(Identifiers{frontend, backend, untagged}))
+}
+
+func TestWeightedRandomIdentifiers(t *testing.T) {
+	frontend := &taggedIdentifier{TestIdentifier: "frontend", weight: 8}
+	backend := &taggedIdentifier{TestIdentifier: "backend", weight: 1}
+	database := &taggedIdentifier{TestIdentifier: "database", weight: 1}
+
+	provider := &scenarioProvider{
+		minResources:   1,
+		maxResources:   1,
+		allIdentifiers: Identifiers{frontend, backend, database},
+		rng:            rand.New(rand.NewSource(42)),
+	}
+	provider.weights = provider.selection.identifierWeights(provider.allIdentifiers)
+
+	require.NoError(t, provider.checkBounds())
+
+	picks := make(map[Identifier]int)
+
+	for i := 0; i < 1000; i++ {
+		identifiers := provider.randomIdentifiers()
+		require.Equal(t, 1, len(identifiers))
+		picks[identifiers[0]]++
+	}
+
+	require.Greater(t, picks[frontend], 4*picks[backend])
+	require.Greater(t, picks[frontend], 4*picks[database])
+	require.Greater(t, picks[database], 0)
+
+	// weighted identifiers are picked without replacement as well.
+	provider.minResources = 3
+	provider.maxResources = 3
+
+	for i := 0; i < 100; i++ {
+		require.ElementsMatch(t, provider.allIdentifiers, provider.randomIdentifiers())
+	}
+}
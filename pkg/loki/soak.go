@@ -0,0 +1,167 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	durationKey    = "duration"
+	intervalKey    = "interval"
+	arrivalKey     = "arrival"
+	fixedArrival   = "fixed"
+	poissonArrival = "poisson"
+)
+
+// soak defines random scenarios which are generated continuously for a wall-clock duration instead of a fixed count.
+type soak struct {
+	// duration is the time for which scenarios are injected, starting after pre-defined scenarios of system.
+	duration time.Duration
+	// interval is the time between start of consecutive scenarios, mean time between them for poisson arrival.
+	interval time.Duration
+	// arrival determines how time between consecutive scenarios is distributed, one of fixed or poisson.
+	arrival string
+}
+
+func parseSoak(soakConf map[string]interface{}) (*soak, error) {
+	durationValue, ok := soakConf[durationKey]
+	if !ok {
+		return nil, errors.Errorf("'%s' field is mandatory in '%s' section", durationKey, randomKey)
+	}
+
+	duration, err := parseDuration(durationKey, durationValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration <= 0 {
+		return nil, errors.Errorf("'%s' should be greater than 0", durationKey)
+	}
+
+	chaosSoak := &soak{
+		duration: duration,
+		arrival:  fixedArrival,
+	}
+
+	if intervalValue, ok := soakConf[intervalKey]; ok {
+		if chaosSoak.interval, err = parseDuration(intervalKey, intervalValue); err != nil {
+			return nil, err
+		}
+
+		if chaosSoak.interval < 0 {
+			return nil, errors.Errorf("'%s' should not be negative", intervalKey)
+		}
+	}
+
+	if arrivalValue, ok := soakConf[arrivalKey]; ok {
+		arrival, ok := arrivalValue.(string)
+		if !ok {
+			return nil, errors.Errorf(strTypeErrMsg, arrivalKey)
+		}
+
+		if arrival != fixedArrival && arrival != poissonArrival {
+			return nil, errors.Errorf("unsupported %s '%s', should be one of '%s' or '%s'", arrivalKey, arrival,
+				fixedArrival, poissonArrival)
+		}
+
+		if arrival == poissonArrival && chaosSoak.interval == 0 {
+			return nil, errors.Errorf("'%s' is mandatory for '%s' %s", intervalKey, poissonArrival, arrivalKey)
+		}
+
+		chaosSoak.arrival = arrival
+	}
+
+	return chaosSoak, nil
+}
+
+// gap returns the time after which next scenario starts. Gaps of poisson arrival are exponentially distributed with
+// interval as mean.
+func (s *soak) gap(rng *rand.Rand) time.Duration {
+	if s.arrival == poissonArrival {
+		return time.Duration(rng.ExpFloat64() * float64(s.interval))
+	}
+
+	return s.interval
+}
+
+// soakScenario generates next random scenario of soak once it's due. It returns false once soak duration is over or
+// ctx is done while waiting for next scenario.
+func (sp *scenarioProvider) soakScenario(ctx context.Context) (*scenario, bool, error) {
+	if sp.deadline.IsZero() {
+		sp.next = time.Now()
+		sp.deadline = sp.next.Add(sp.soak.duration)
+	}
+
+	if !sp.next.Before(sp.deadline) {
+		return nil, false, nil
+	}
+
+	if wait := time.Until(sp.next); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil, false, nil
+		case <-timer.C:
+		}
+	}
+
+	// scenario which takes longer than the gap delays the next one instead of triggering a burst of scenarios.
+	sp.next = time.Now().Add(sp.soak.gap(sp.rng))
+
+	// scenarios of soak may repeat as long as they aren't excluded, as a long soak would otherwise run out of them.
+	for clashes := 0; clashes < maxTotalClashes; clashes++ {
+		identifiers := sp.randomIdentifiers()
+		if sp.excluded(identifiers) {
+			continue
+		}
+
+		return &scenario{
+			timeout:           sp.randomTimeout,
+			recoveryObjective: sp.randomObjective,
+			identifiers:       identifiers,
+		}, true, nil
+	}
+
+	return nil, false, errors.New("too many clashes with exclusions")
+}
+
+// plannedSoak summarizes soak of provider for plan.
+func (sp *scenarioProvider) plannedSoak() *PlannedSoak {
+	planned := &PlannedSoak{
+		Duration:     sp.soak.duration.String(),
+		Interval:     sp.soak.interval.String(),
+		Arrival:      sp.soak.arrival,
+		MinResources: sp.minResources,
+		MaxResources: sp.largestResources(),
+		Timeout:      sp.randomTimeout.String(),
+	}
+
+	if sp.soak.interval > 0 {
+		planned.ExpectedScenarios = int64((sp.soak.duration + sp.soak.interval - 1) / sp.soak.interval)
+	}
+
+	if sp.randomObjective > 0 {
+		planned.RecoveryObjective = sp.randomObjective.String()
+	}
+
+	return planned
+}
@@ -0,0 +1,163 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loki
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const soakConf = `
+ready:
+  after: 0s
+systems:
+- type: test-system
+  name: testing
+  resources:
+  - resource1
+  - resource2
+  - resource3
+destroy:
+  scenarios:
+  - system: testing
+    resources:
+    - resource1
+  - system: testing
+    random:
+%s
+    minResources: 1
+    maxResources: 2
+`
+
+func TestParseSoak(t *testing.T) {
+	RegisterTestSystem(t)
+
+	tests := []struct {
+		description string
+		random      string
+		expected    *soak
+		expectedErr bool
+	}{
+		{
+			description: "fixed arrival by default",
+			random:      "      duration: 48h\n      interval: 10m",
+			expected:    &soak{duration: 48 * time.Hour, interval: 10 * time.Minute, arrival: fixedArrival},
+		},
+		{
+			description: "poisson arrival",
+			random:      "      duration: 1h\n      interval: 1m\n      arrival: poisson",
+			expected:    &soak{duration: time.Hour, interval: time.Minute, arrival: poissonArrival},
+		},
+		{
+			description: "back to back scenarios",
+			random:      "      duration: 1h",
+			expected:    &soak{duration: time.Hour, arrival: fixedArrival},
+		},
+		{
+			description: "missing duration",
+			random:      "      interval: 1m",
+			expectedErr: true,
+		},
+		{
+			description: "poisson arrival without interval",
+			random:      "      duration: 1h\n      arrival: poisson",
+			expectedErr: true,
+		},
+		{
+			description: "unsupported arrival",
+			random:      "      duration: 1h\n      interval: 1m\n      arrival: bursty",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			configuration := NewConfig()
+
+			err := configuration.Parse([]byte(fmt.Sprintf(soakConf, test.random)))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			provider := configuration.scenarioProviders["testing"]
+			require.Equal(t, test.expected, provider.soak)
+			require.Equal(t, int64(0), provider.random)
+			require.Equal(t, true, provider.randomized())
+		})
+	}
+}
+
+func TestScenarioSoak(t *testing.T) {
+	RegisterTestSystem(t)
+
+	configuration := NewConfig()
+
+	err := configuration.Parse([]byte(fmt.Sprintf(soakConf, "      duration: 500ms\n      interval: 200ms")))
+	require.NoError(t, err)
+
+	provider := configuration.scenarioProviders["testing"]
+	system := configuration.systems["testing"]
+
+	scenario, ok, err := provider.scenario(context.Background(), system)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+	require.Equal(t, Identifiers{TestIdentifier("resource1")}, scenario.identifiers)
+
+	start := time.Now()
+	soakScenarios := 0
+
+	for {
+		scenario, ok, err := provider.scenario(context.Background(), system)
+		require.NoError(t, err)
+
+		if !ok {
+			break
+		}
+
+		require.Equal(t, 1, len(scenario.identifiers))
+		soakScenarios++
+	}
+
+	require.Equal(t, 3, soakScenarios)
+	require.True(t, time.Since(start) >= 400*time.Millisecond)
+
+	configuration = NewConfig()
+
+	err = configuration.Parse([]byte(fmt.Sprintf(soakConf, "      duration: 1h\n      interval: 1h")))
+	require.NoError(t, err)
+
+	provider = configuration.scenarioProviders["testing"]
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for i := 0; i < 2; i++ {
+		_, ok, err := provider.scenario(ctx, system)
+		require.NoError(t, err)
+		require.Equal(t, true, ok)
+	}
+
+	cancel()
+
+	_, ok, err = provider.scenario(ctx, system)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+}
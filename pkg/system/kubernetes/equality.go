@@ -15,12 +15,18 @@ limitations under the License.
 package kubernetes
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/narahari92/loki/pkg/loki"
 )
 
 const (
@@ -53,10 +59,10 @@ var semanticEquality = conversion.EqualitiesOrDie(
 )
 
 func isEqual(desired, actual *unstructured.Unstructured) bool {
-	if ok := semanticEquality.DeepEqual(desired.GetLabels(), actual.GetLabels()); !ok {
-		return false
-	}
+	return semanticEquality.DeepEqual(desired.GetLabels(), actual.GetLabels()) && isStatusEqual(desired, actual)
+}
 
+func isStatusEqual(desired, actual *unstructured.Unstructured) bool {
 	desiredStatusValue, ok, err := unstructured.NestedFieldNoCopy(desired.Object, status)
 	if err != nil || !ok {
 		return true
@@ -154,3 +160,108 @@ func compareConditions(desiredStatus, actualStatus map[string]interface{}) bool
 
 	return true
 }
+
+// driftOf describes the ways in which actual deviates from desired, as compared by isEqual. It returns nil if both are
+// equal.
+func driftOf(id loki.ID, desired, actual *unstructured.Unstructured) []loki.Drift {
+	var drift []loki.Drift
+
+	if messages := labelDrift(desired.GetLabels(), actual.GetLabels()); len(messages) != 0 {
+		drift = append(drift, loki.Drift{
+			ID:      id,
+			Type:    loki.LabelDrift,
+			Message: strings.Join(messages, "; "),
+		})
+	}
+
+	if isStatusEqual(desired, actual) {
+		return drift
+	}
+
+	desiredConditions, ok, err := unstructured.NestedSlice(desired.Object, status, conditions)
+	if err != nil || !ok {
+		return append(drift, loki.Drift{
+			ID:      id,
+			Type:    loki.StatusDrift,
+			Message: "status doesn't match the desired status",
+		})
+	}
+
+	actualConditions, _, _ := unstructured.NestedSlice(actual.Object, status, conditions)
+
+	return append(drift, loki.Drift{
+		ID:      id,
+		Type:    loki.ConditionDrift,
+		Message: strings.Join(conditionDrift(desiredConditions, actualConditions), "; "),
+	})
+}
+
+func labelDrift(desired, actual map[string]string) []string {
+	var messages []string
+
+	for key, desiredValue := range desired {
+		actualValue, ok := actual[key]
+
+		switch {
+		case !ok:
+			messages = append(messages, fmt.Sprintf("label '%s' is missing, expected '%s'", key, desiredValue))
+		case actualValue != desiredValue:
+			messages = append(messages, fmt.Sprintf("label '%s' is '%s', expected '%s'", key, actualValue, desiredValue))
+		}
+	}
+
+	for key, actualValue := range actual {
+		if _, ok := desired[key]; !ok {
+			messages = append(messages, fmt.Sprintf("unexpected label '%s' with value '%s'", key, actualValue))
+		}
+	}
+
+	sort.Strings(messages)
+
+	return messages
+}
+
+func conditionDrift(desired, actual []interface{}) []string {
+	desiredStatuses := conditionStatuses(desired)
+	actualStatuses := conditionStatuses(actual)
+
+	var messages []string
+
+	for conditionType, desiredStatus := range desiredStatuses {
+		actualStatus, ok := actualStatuses[conditionType]
+
+		switch {
+		case !ok:
+			messages = append(messages, fmt.Sprintf("condition '%s' is missing, expected status '%v'", conditionType, desiredStatus))
+		case !semanticEquality.DeepEqual(desiredStatus, actualStatus):
+			messages = append(messages, fmt.Sprintf("condition '%s' has status '%v', expected '%v'",
+				conditionType, actualStatus, desiredStatus))
+		}
+	}
+
+	for conditionType := range actualStatuses {
+		if _, ok := desiredStatuses[conditionType]; !ok {
+			messages = append(messages, fmt.Sprintf("unexpected condition '%s'", conditionType))
+		}
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, "conditions don't match the desired conditions")
+	}
+
+	sort.Strings(messages)
+
+	return messages
+}
+
+func conditionStatuses(conditionValues []interface{}) map[string]interface{} {
+	statuses := make(map[string]interface{})
+
+	for _, conditionValue := range conditionValues {
+		if condition, ok := conditionValue.(map[string]interface{}); ok {
+			statuses[fmt.Sprintf("%v", condition[conditionType])] = condition[conditionStatus]
+		}
+	}
+
+	return statuses
+}
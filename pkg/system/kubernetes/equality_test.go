@@ -15,12 +15,19 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/narahari92/loki/pkg/loki"
 )
 
 func TestEquality(t *testing.T) {
@@ -71,3 +78,90 @@ func TestEquality(t *testing.T) {
 	equality := isEqual(pod1, pod2)
 	require.Equal(t, true, equality)
 }
+
+func TestConditionDrift(t *testing.T) {
+	desired := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+					map[string]interface{}{"type": "Progressing", "status": "True"},
+				},
+			},
+		},
+	}
+
+	actual := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "False"},
+				},
+			},
+		},
+	}
+
+	drift := driftOf("deploy", desired, actual)
+	require.Equal(t, []loki.Drift{
+		{
+			ID:   "deploy",
+			Type: loki.ConditionDrift,
+			Message: "condition 'Available' has status 'False', expected 'True'; " +
+				"condition 'Progressing' is missing, expected status 'True'",
+		},
+	}, drift)
+
+	require.Equal(t, 0, len(driftOf("deploy", desired, desired)))
+}
+
+func TestValidationDrift(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewFakeClient(labeledConfigMap("cm-a", "front"), labeledConfigMap("cm-b", "back"))
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+		},
+	}
+
+	err := system.Load(ctx)
+	require.NoError(t, err)
+
+	err = k8sClient.Delete(ctx, labeledConfigMap("cm-b", "back"))
+	require.NoError(t, err)
+
+	configMap := &v1.ConfigMap{}
+	err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: "cm-a"}, configMap)
+	require.NoError(t, err)
+
+	configMap.Labels["tier"] = "back"
+	err = k8sClient.Update(ctx, configMap)
+	require.NoError(t, err)
+
+	err = k8sClient.Create(ctx, labeledConfigMap("cm-c", "front"))
+	require.NoError(t, err)
+
+	ok, err := system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+	require.Equal(t, []loki.Drift{
+		{
+			ID:      "loki:kubernetes-resource:/v1, Kind=ConfigMap, test-ns/cm-a",
+			Type:    loki.LabelDrift,
+			Message: "label 'tier' is 'back', expected 'front'",
+		},
+		{
+			ID:      "loki:kubernetes-resource:/v1, Kind=ConfigMap, test-ns/cm-b",
+			Type:    loki.MissingDrift,
+			Message: "resource doesn't exist",
+		},
+		{
+			ID:      "loki:kubernetes-resource:/v1, Kind=ConfigMap, test-ns/cm-c",
+			Type:    loki.ExtraDrift,
+			Message: "resource isn't part of the loaded state",
+		},
+	}, system.Drift())
+}
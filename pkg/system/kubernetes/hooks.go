@@ -0,0 +1,308 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+const (
+	snapshotHookKey = "kubernetesSnapshot"
+	podLogsHookKey  = "podLogs"
+	systemKey       = "system"
+	fileKey         = "file"
+	dirKey          = "dir"
+	containerKey    = "container"
+	tailLinesKey    = "tailLines"
+)
+
+// snapshotHook writes the current state of resources of a kubernetes system into a file, or into report when file
+// isn't defined. Unlike AsJSON, it doesn't reload the desired state of system, so it can be used at any phase.
+type snapshotHook struct {
+	system *System
+	file   string
+}
+
+// podLogsHook collects logs of pods of a kubernetes system into a directory, or into report when directory isn't
+// defined.
+type podLogsHook struct {
+	system        *System
+	namespace     string
+	labelSelector string
+	container     string
+	tailLines     *int64
+	dir           string
+}
+
+// NewSnapshotHookParser instantiates HookParser which parses hooks taking snapshot of kubernetes system.
+func NewSnapshotHookParser(config *loki.Config) loki.HookParser {
+	return loki.HookParserFunc(func(hookConf map[string]interface{}) (func(context.Context) error, error) {
+		snapshotConf, ok := hookConf[snapshotHookKey].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type map", snapshotHookKey)
+		}
+
+		system, err := hookSystem(config, snapshotConf)
+		if err != nil {
+			return nil, err
+		}
+
+		hook := &snapshotHook{system: system}
+
+		if hook.file, err = optionalString(snapshotConf, fileKey); err != nil {
+			return nil, err
+		}
+
+		return hook.run, nil
+	})
+}
+
+// NewPodLogsHookParser instantiates HookParser which parses hooks collecting logs of pods of kubernetes system.
+func NewPodLogsHookParser(config *loki.Config) loki.HookParser {
+	return loki.HookParserFunc(func(hookConf map[string]interface{}) (func(context.Context) error, error) {
+		podLogsConf, ok := hookConf[podLogsHookKey].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("field '%s' should be of type map", podLogsHookKey)
+		}
+
+		system, err := hookSystem(config, podLogsConf)
+		if err != nil {
+			return nil, err
+		}
+
+		hook := &podLogsHook{system: system}
+
+		for key, field := range map[string]*string{
+			namespaceKey:     &hook.namespace,
+			labelSelectorKey: &hook.labelSelector,
+			containerKey:     &hook.container,
+			dirKey:           &hook.dir,
+		} {
+			if *field, err = optionalString(podLogsConf, key); err != nil {
+				return nil, err
+			}
+		}
+
+		if tailLinesValue, ok := podLogsConf[tailLinesKey]; ok {
+			tailLines, ok := tailLinesValue.(float64)
+			if !ok || tailLines != float64(int64(tailLines)) || tailLines < 1 {
+				return nil, errors.Errorf("'%s' field should be a positive integer", tailLinesKey)
+			}
+
+			lines := int64(tailLines)
+			hook.tailLines = &lines
+		}
+
+		return hook.run, nil
+	})
+}
+
+// run writes the current state of resources of system.
+func (h *snapshotHook) run(ctx context.Context) error {
+	objects, err := h.system.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal snapshot")
+	}
+
+	if h.file == "" {
+		loki.RecordOutput(ctx, string(snapshot))
+		return nil
+	}
+
+	file := scenarioFile(ctx, h.file)
+	if err := ioutil.WriteFile(file, snapshot, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write snapshot into file '%s'", file)
+	}
+
+	loki.RecordOutput(ctx, fmt.Sprintf("snapshot of %d resource(s) written into file '%s'", len(objects), file))
+
+	return nil
+}
+
+// run collects logs of all containers, or of the container defined, of pods matching namespace and label selector.
+// Logs of all containers are collected even if some of them fail, and their errors are returned together.
+func (h *podLogsHook) run(ctx context.Context) error {
+	if h.system.restConfig == nil {
+		return errors.New("kubernetes client isn't configured")
+	}
+
+	clientset, err := kubernetes.NewForConfig(h.system.restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create kubernetes clientset")
+	}
+
+	pods, err := clientset.CoreV1().Pods(h.namespace).List(ctx, metav1.ListOptions{LabelSelector: h.labelSelector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list pods")
+	}
+
+	if h.dir != "" {
+		if err := os.MkdirAll(h.dir, 0755); err != nil {
+			return errors.Wrapf(err, "failed to create directory '%s'", h.dir)
+		}
+	}
+
+	logs := &bytes.Buffer{}
+
+	var failures []string
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if h.container != "" && container.Name != h.container {
+				continue
+			}
+
+			podLogs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+				Container: container.Name,
+				TailLines: h.tailLines,
+			}).DoRaw(ctx)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("failed to get logs of container '%s' of pod '%s/%s': %s",
+					container.Name, pod.Namespace, pod.Name, err))
+				continue
+			}
+
+			if h.dir == "" {
+				fmt.Fprintf(logs, "==> %s/%s/%s <==\n%s\n", pod.Namespace, pod.Name, container.Name, podLogs)
+				continue
+			}
+
+			file := scenarioFile(ctx, filepath.Join(h.dir,
+				fmt.Sprintf("%s_%s_%s.log", pod.Namespace, pod.Name, container.Name)))
+			if err := ioutil.WriteFile(file, podLogs, 0644); err != nil {
+				failures = append(failures, fmt.Sprintf("failed to write logs into file '%s': %s", file, err))
+				continue
+			}
+
+			fmt.Fprintf(logs, "logs of container '%s' of pod '%s/%s' written into file '%s'\n",
+				container.Name, pod.Namespace, pod.Name, file)
+		}
+	}
+
+	loki.RecordOutput(ctx, logs.String())
+
+	if len(failures) > 0 {
+		return errors.Errorf("failed to collect logs of %d container(s):\n%s", len(failures),
+			strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
+
+// scenarioFile returns the file into which hook writes when executed for a scenario, which is file prefixed by the
+// system and index of scenario so that scenarios don't overwrite files of each other. file is returned as is when hook
+// isn't executed for a scenario.
+func scenarioFile(ctx context.Context, file string) string {
+	info, ok := loki.ScenarioFromContext(ctx)
+	if !ok {
+		return file
+	}
+
+	// compound scenarios have names of all their systems separated by comma.
+	system := strings.ReplaceAll(info.System, ",", "-")
+
+	return filepath.Join(filepath.Dir(file), fmt.Sprintf("%s_%d_%s", system, info.Index, filepath.Base(file)))
+}
+
+// snapshot returns the current objects of all resources defined in system. Resources which no longer exist are left
+// out.
+func (s *System) snapshot(ctx context.Context) ([]unstructured.Unstructured, error) {
+	objects := make([]unstructured.Unstructured, 0)
+
+	for _, resourceIdentifier := range s.resourceIdentifiers {
+		if !resourceIdentifier.isGroup() {
+			object := &unstructured.Unstructured{}
+			object.SetGroupVersionKind(resourceIdentifier.GroupVersionKind)
+
+			err := s.k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: resourceIdentifier.Namespace, Name: resourceIdentifier.Name},
+				object,
+			)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get kubernetes resource")
+			}
+
+			objects = append(objects, *object)
+
+			continue
+		}
+
+		list, err := s.list(ctx, s.k8sClient, resourceIdentifier)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, list.Items...)
+	}
+
+	return objects, nil
+}
+
+// hookSystem returns the kubernetes system referred by hook definition.
+func hookSystem(config *loki.Config, hookConf map[string]interface{}) (*System, error) {
+	systemName, ok := hookConf[systemKey].(string)
+	if !ok {
+		return nil, errors.Errorf("'%s' field is mandatory and should be of type string", systemKey)
+	}
+
+	system, ok := config.System(systemName).(*System)
+	if !ok {
+		return nil, errors.Errorf("'%s' is not a kubernetes system", systemName)
+	}
+
+	return system, nil
+}
+
+// optionalString returns the value of optional string field of hook definition.
+func optionalString(hookConf map[string]interface{}, key string) (string, error) {
+	value, ok := hookConf[key]
+	if !ok {
+		return "", nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("'%s' field should be of type string", key)
+	}
+
+	return str, nil
+}
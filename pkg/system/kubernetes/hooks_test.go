@@ -0,0 +1,139 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestSnapshotHook(t *testing.T) {
+	ctx := context.Background()
+	configMap := func(name string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+				Labels:    map[string]string{"app": "web"},
+			},
+		}
+	}
+
+	system := NewSystem()
+	system.k8sClient = fake.NewFakeClient(configMap("cm-a"), configMap("cm-b"))
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+			Name:             "cm-a",
+		},
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+			Name:             "deleted-cm",
+		},
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+			LabelSelector:    "app=web",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hook := &snapshotHook{
+		system: system,
+		file:   filepath.Join(dir, "snapshot.json"),
+	}
+
+	err = hook.run(ctx)
+	require.NoError(t, err)
+
+	snapshot, err := ioutil.ReadFile(hook.file)
+	require.NoError(t, err)
+
+	var objects []unstructured.Unstructured
+	err = json.Unmarshal(snapshot, &objects)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.GetName())
+	}
+
+	require.Equal(t, []string{"cm-a", "cm-a", "cm-b"}, names)
+
+	// snapshots of scenarios are written into files of their own.
+	for index := 1; index <= 2; index++ {
+		scenarioCtx := loki.ContextWithScenario(ctx, loki.ScenarioInfo{System: "cluster-a,cluster-b", Index: index})
+
+		err = hook.run(scenarioCtx)
+		require.NoError(t, err)
+	}
+
+	for _, file := range []string{"cluster-a-cluster-b_1_snapshot.json", "cluster-a-cluster-b_2_snapshot.json"} {
+		_, err = os.Stat(filepath.Join(dir, file))
+		require.NoError(t, err)
+	}
+}
+
+func TestHookParsers(t *testing.T) {
+	config := loki.NewConfig()
+
+	tests := []struct {
+		description string
+		parser      loki.HookParser
+		hookConf    map[string]interface{}
+	}{
+		{
+			description: "malformed snapshot hook",
+			parser:      NewSnapshotHookParser(config),
+			hookConf:    map[string]interface{}{snapshotHookKey: "cluster-a"},
+		},
+		{
+			description: "undefined system of snapshot hook",
+			parser:      NewSnapshotHookParser(config),
+			hookConf:    map[string]interface{}{snapshotHookKey: map[string]interface{}{systemKey: "cluster-a"}},
+		},
+		{
+			description: "missing system of pod logs hook",
+			parser:      NewPodLogsHookParser(config),
+			hookConf:    map[string]interface{}{podLogsHookKey: map[string]interface{}{namespaceKey: "test-ns"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			_, err := test.parser.Parse(test.hookConf)
+			require.Error(t, err)
+		})
+	}
+}
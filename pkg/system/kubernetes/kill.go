@@ -21,6 +21,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/narahari92/loki/pkg/loki"
 )
@@ -31,18 +32,32 @@ type Killer struct {
 	*System
 }
 
-// Kill deletes the kubernetes resources represented by identifiers.
+// Kill deletes the kubernetes resources represented by identifiers. If a resource was replaced by its controller since
+// the system was loaded, the replacement is deleted instead. Deleted objects are remembered so that validation doesn't
+// consider them recovered until they're gone.
 func (k *Killer) Kill(ctx context.Context, identifiers ...loki.Identifier) error {
 	for _, identifier := range identifiers {
-		resourceIdentifier, ok := identifier.(*ResourceIdentifier)
+		loadedIdentifier, ok := asResourceIdentifier(identifier)
 		if !ok {
 			return errors.New("unsupported identifier passed to kubernetes killer")
 		}
 
+		resourceIdentifier := k.current(*loadedIdentifier)
+
 		object := &unstructured.Unstructured{}
 		object.SetGroupVersionKind(resourceIdentifier.GroupVersionKind)
-		object.SetNamespace(resourceIdentifier.Namespace)
-		object.SetName(resourceIdentifier.Name)
+
+		if err := k.k8sClient.Get(
+			ctx,
+			types.NamespacedName{Namespace: resourceIdentifier.Namespace, Name: resourceIdentifier.Name},
+			object,
+		); err != nil {
+			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return errors.Wrapf(err, "failed to get kubernetes resource")
+		}
 
 		if err := k.k8sClient.Delete(ctx, object); err != nil {
 			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
@@ -51,6 +66,12 @@ func (k *Killer) Kill(ctx context.Context, identifiers ...loki.Identifier) error
 
 			return errors.Wrapf(err, "failed to delete kubernetes resource")
 		}
+
+		// the object with this UID may linger in informer cache, or be terminating, after deletion. It must not be
+		// mistaken for a recovered resource during validation.
+		if object.GetUID() != "" {
+			k.killed[object.GetUID()] = true
+		}
 	}
 
 	return nil
@@ -15,9 +15,11 @@ limitations under the License.
 package kubernetes
 
 import (
-	"errors"
-
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/narahari92/loki/pkg/loki"
 )
@@ -35,14 +37,87 @@ type ResourceIdentifier struct {
 	Name string
 	// Namespace represents the namespace in which kubernetes resources lies. It should be empty for cluster scoped resoruces.
 	Namespace string
+	// LabelSelector restricts the resources to the ones whose labels match it. It is empty for individual resources.
+	LabelSelector string
+	// FieldSelector restricts the resources to the ones whose fields match it. It is empty for individual resources.
+	FieldSelector string
 }
 
 // ID returns the unique identifier of kubernetes resource.
 func (r *ResourceIdentifier) ID() loki.ID {
-	return loki.ID(kubernetesResource + ":" + r.GroupVersionKind.String() + ", " + r.Namespace + "/" + r.Name)
+	id := kubernetesResource + ":" + r.GroupVersionKind.String() + ", " + r.Namespace + "/" + r.Name
+
+	if r.LabelSelector != "" {
+		id += ", " + labelSelectorKey + "=" + r.LabelSelector
+	}
+
+	if r.FieldSelector != "" {
+		id += ", " + fieldSelectorKey + "=" + r.FieldSelector
+	}
+
+	return loki.ID(id)
+}
+
+// loadedResource is the identifier of a loaded kubernetes resource which carries labels of the resource as its tags, so
+// that random scenarios can select resources by their labels.
+type loadedResource struct {
+	*ResourceIdentifier
+	labels map[string]string
+}
+
+// Tags returns the labels of kubernetes resource.
+func (r *loadedResource) Tags() map[string]string {
+	return r.labels
+}
+
+// Weight returns 0 so that kubernetes resources are only weighted by weights of random scenarios.
+func (r *loadedResource) Weight() float64 {
+	return 0
+}
+
+// asResourceIdentifier returns the ResourceIdentifier represented by identifier, if it is a kubernetes identifier.
+func asResourceIdentifier(identifier loki.Identifier) (*ResourceIdentifier, bool) {
+	switch typedIdentifier := identifier.(type) {
+	case *ResourceIdentifier:
+		return typedIdentifier, true
+	case *loadedResource:
+		return typedIdentifier.ResourceIdentifier, true
+	default:
+		return nil, false
+	}
+}
+
+// isGroup determines whether identifier represents a group of resources rather than an individual resource.
+func (r *ResourceIdentifier) isGroup() bool {
+	return r.Name == "" || r.LabelSelector != "" || r.FieldSelector != ""
+}
+
+// listOptions returns the options to list the resources represented by identifier.
+func (r *ResourceIdentifier) listOptions() (*client.ListOptions, error) {
+	listOptions := &client.ListOptions{Namespace: r.Namespace}
+
+	if r.LabelSelector != "" {
+		selector, err := labels.Parse(r.LabelSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse label selector '%s'", r.LabelSelector)
+		}
+
+		listOptions.LabelSelector = selector
+	}
+
+	if r.FieldSelector != "" {
+		selector, err := fields.ParseSelector(r.FieldSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse field selector '%s'", r.FieldSelector)
+		}
+
+		listOptions.FieldSelector = selector
+	}
+
+	return listOptions, nil
 }
 
-// Register registers the kubernetes system, destroyer and killer with loki.
+// Register registers the kubernetes system, destroyer, killer and hook parsers with loki.
 func Register() {
 	loki.RegisterSystem(system, func() loki.System {
 		return NewSystem()
@@ -58,4 +133,6 @@ func Register() {
 			System: kubernetesSystem,
 		}, nil
 	})
+	loki.RegisterHookParser(snapshotHookKey, NewSnapshotHookParser)
+	loki.RegisterHookParser(podLogsHookKey, NewPodLogsHookParser)
 }
@@ -53,6 +53,7 @@ resources:
 	k8sClient, identifiers := createClientAndIdentifiers(t)
 	system := NewSystem()
 	system.k8sClient = k8sClient
+	system.restConfig = cfg
 	system.resourceIdentifiers = identifiers
 	killer := &Killer{
 		System: system,
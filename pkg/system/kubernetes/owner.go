@@ -0,0 +1,130 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	statefulSetKind              = "StatefulSet"
+	daemonSetKind                = "DaemonSet"
+	jobKind                      = "Job"
+	podTemplateHashLabel         = "pod-template-hash"
+	jobCompletionIndexAnnotation = "batch.kubernetes.io/job-completion-index"
+)
+
+// ownerKey identifies the group of objects which are managed by the same controller and are interchangeable with each
+// other. A killed object is considered to be recovered if its controller creates a replacement with same ownerKey.
+type ownerKey struct {
+	ownerKind string
+	ownerName string
+	// discriminator narrows down the replacements within the objects of same controller. It is the pod template hash
+	// for objects managed by replica sets, the ordinal for objects managed by stateful sets, the node for objects managed
+	// by daemon sets and the completion index for objects managed by indexed jobs.
+	discriminator string
+}
+
+// ownerKeyOf returns the ownerKey of object if it is managed by a controller.
+func ownerKeyOf(object *unstructured.Unstructured) (ownerKey, bool) {
+	controller := controllerOf(object)
+	if controller == nil {
+		return ownerKey{}, false
+	}
+
+	key := ownerKey{
+		ownerKind: controller.Kind,
+		ownerName: controller.Name,
+	}
+
+	switch controller.Kind {
+	case statefulSetKind:
+		key.discriminator = object.GetName()[strings.LastIndex(object.GetName(), "-")+1:]
+	case daemonSetKind:
+		// pods of a daemon set are bound to their node, so a pod on another node doesn't replace them.
+		key.discriminator, _, _ = unstructured.NestedString(object.Object, "spec", "nodeName")
+	case jobKind:
+		// pods of a job which isn't indexed don't have completion index and are interchangeable.
+		key.discriminator = object.GetAnnotations()[jobCompletionIndexAnnotation]
+	default:
+		key.discriminator = object.GetLabels()[podTemplateHashLabel]
+	}
+
+	return key, true
+}
+
+func controllerOf(object *unstructured.Unstructured) *metav1.OwnerReference {
+	for _, ownerReference := range object.GetOwnerReferences() {
+		ownerReference := ownerReference
+		if ownerReference.Controller != nil && *ownerReference.Controller {
+			return &ownerReference
+		}
+	}
+
+	return nil
+}
+
+// findReplacement looks for an object created by the controller of a killed object to replace it. Objects whose names
+// are present in claimed are not considered as they're already accounted for by other identifiers.
+func (s *System) findReplacement(
+	ctx context.Context,
+	reader client.Reader,
+	identifier ResourceIdentifier,
+	desired *unstructured.Unstructured,
+	claimed map[string]bool,
+) (*unstructured.Unstructured, error) {
+	key := s.owners[identifier]
+
+	objects := newList(identifier.GroupVersionKind)
+
+	if err := reader.List(ctx, objects, client.InNamespace(identifier.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list replacement candidates")
+	}
+
+	sort.Slice(objects.Items, func(i, j int) bool {
+		return objects.Items[i].GetName() < objects.Items[j].GetName()
+	})
+
+	var replacement *unstructured.Unstructured
+
+	for i := range objects.Items {
+		candidate := &objects.Items[i]
+
+		if claimed[candidate.GetName()] || candidate.GetDeletionTimestamp() != nil || s.killed[candidate.GetUID()] {
+			continue
+		}
+
+		if candidateKey, ok := ownerKeyOf(candidate); !ok || candidateKey != key {
+			continue
+		}
+
+		if isEqual(desired, candidate) {
+			return candidate, nil
+		}
+
+		if replacement == nil {
+			replacement = candidate
+		}
+	}
+
+	return replacement, nil
+}
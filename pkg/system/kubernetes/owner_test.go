@@ -0,0 +1,256 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOwnerAwareValidation(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewFakeClient(replicaSetPod("web-abc-x1"), replicaSetPod("web-abc-x2"))
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Namespace:        "test-ns",
+		},
+	}
+
+	err := system.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(system.Identifiers()))
+
+	killer := &Killer{
+		System: system,
+	}
+
+	podIdentifier := &ResourceIdentifier{
+		GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace:        "test-ns",
+		Name:             "web-abc-x1",
+	}
+
+	err = killer.Kill(ctx, podIdentifier)
+	require.NoError(t, err)
+
+	ok, err := system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+
+	err = k8sClient.Create(ctx, replicaSetPod("web-abc-y1"))
+	require.NoError(t, err)
+
+	ok, err = system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+
+	err = killer.Kill(ctx, podIdentifier)
+	require.NoError(t, err)
+
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "test-ns", Name: "web-abc-y1"}, &v1.Pod{})
+	require.Error(t, err)
+
+	ok, err = system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+}
+
+func TestOwnerKey(t *testing.T) {
+	tests := []struct {
+		description string
+		pod         *v1.Pod
+		expected    ownerKey
+		expectedOk  bool
+	}{
+		{
+			description: "replica set",
+			pod:         replicaSetPod("web-abc-x1"),
+			expected:    ownerKey{ownerKind: "ReplicaSet", ownerName: "web-abc", discriminator: "abc"},
+			expectedOk:  true,
+		},
+		{
+			description: "stateful set",
+			pod:         ownedPod("db-2", "StatefulSet", "db"),
+			expected:    ownerKey{ownerKind: "StatefulSet", ownerName: "db", discriminator: "2"},
+			expectedOk:  true,
+		},
+		{
+			description: "daemon set",
+			pod:         daemonSetPod("agent-x1", "node-a"),
+			expected:    ownerKey{ownerKind: "DaemonSet", ownerName: "agent", discriminator: "node-a"},
+			expectedOk:  true,
+		},
+		{
+			description: "indexed job",
+			pod: func() *v1.Pod {
+				pod := ownedPod("batch-1-x1", "Job", "batch")
+				pod.Annotations = map[string]string{jobCompletionIndexAnnotation: "1"}
+				return pod
+			}(),
+			expected:   ownerKey{ownerKind: "Job", ownerName: "batch", discriminator: "1"},
+			expectedOk: true,
+		},
+		{
+			description: "job",
+			pod:         ownedPod("batch-x1", "Job", "batch"),
+			expected:    ownerKey{ownerKind: "Job", ownerName: "batch"},
+			expectedOk:  true,
+		},
+		{
+			description: "no controller",
+			pod: func() *v1.Pod {
+				pod := ownedPod("standalone", "ReplicaSet", "web")
+				pod.OwnerReferences = nil
+				return pod
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.pod)
+			require.NoError(t, err)
+
+			key, ok := ownerKeyOf(&unstructured.Unstructured{Object: content})
+			require.Equal(t, test.expectedOk, ok)
+			require.Equal(t, test.expected, key)
+		})
+	}
+}
+
+func TestDaemonSetReplacementOnSameNode(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewFakeClient(daemonSetPod("agent-x1", "node-a"), daemonSetPod("agent-x2", "node-b"))
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Namespace:        "test-ns",
+		},
+	}
+
+	err := system.Load(ctx)
+	require.NoError(t, err)
+
+	killer := &Killer{
+		System: system,
+	}
+
+	err = killer.Kill(ctx, &ResourceIdentifier{
+		GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace:        "test-ns",
+		Name:             "agent-x1",
+	})
+	require.NoError(t, err)
+
+	// pod of same daemon set on another node doesn't replace the killed pod.
+	err = k8sClient.Create(ctx, daemonSetPod("agent-y2", "node-b"))
+	require.NoError(t, err)
+
+	ok, err := system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+
+	err = k8sClient.Create(ctx, daemonSetPod("agent-y1", "node-a"))
+	require.NoError(t, err)
+
+	ok, err = system.Validate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+}
+
+func daemonSetPod(name, node string) *v1.Pod {
+	pod := ownedPod(name, "DaemonSet", "agent")
+	pod.Spec.NodeName = node
+
+	return pod
+}
+
+// ownedPod returns a pod in test-ns managed by controller of given kind and name.
+func ownedPod(name, kind, owner string) *v1.Pod {
+	controller := true
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "apps/v1",
+					Kind:       kind,
+					Name:       owner,
+					UID:        types.UID(owner + "-uid"),
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  owner,
+					Image: owner + "-image",
+				},
+			},
+		},
+	}
+}
+
+func replicaSetPod(name string) *v1.Pod {
+	controller := true
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				"app":                "web",
+				podTemplateHashLabel: "abc",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       "web-abc",
+					UID:        "web-abc-uid",
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "web",
+					Image: "web-image",
+				},
+			},
+		},
+	}
+}
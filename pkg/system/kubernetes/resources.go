@@ -77,6 +77,32 @@ func parseResources(resources []interface{}) ([]*ResourceIdentifier, error) {
 			resIdent.Namespace = namespace
 		}
 
+		if labelSelectorValue, ok := k8sResource[labelSelectorKey]; ok {
+			labelSelector, ok := labelSelectorValue.(string)
+			if !ok {
+				return nil, errors.Errorf(strTypeErrMsg, labelSelectorKey)
+			}
+
+			resIdent.LabelSelector = labelSelector
+		}
+
+		if fieldSelectorValue, ok := k8sResource[fieldSelectorKey]; ok {
+			fieldSelector, ok := fieldSelectorValue.(string)
+			if !ok {
+				return nil, errors.Errorf(strTypeErrMsg, fieldSelectorKey)
+			}
+
+			resIdent.FieldSelector = fieldSelector
+		}
+
+		if resIdent.Name != "" && (resIdent.LabelSelector != "" || resIdent.FieldSelector != "") {
+			return nil, errors.Errorf("'%s' field can't be combined with '%s' or '%s'", nameKey, labelSelectorKey, fieldSelectorKey)
+		}
+
+		if _, err := resIdent.listOptions(); err != nil {
+			return nil, err
+		}
+
 		identifiers = append(identifiers, resIdent)
 	}
 
@@ -0,0 +1,118 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestSelectors(t *testing.T) {
+	systemSectionYaml := `
+resources:
+- apiVersion: v1
+  kind: ConfigMap
+  namespace: test-ns
+  labelSelector: tier in (frontend, backend)
+`
+	destroySectionYaml := `
+system: test-system
+resources:
+- apiVersion: v1
+  kind: ConfigMap
+  namespace: test-ns
+  labelSelector: tier=frontend
+`
+	ctx := context.Background()
+	k8sClient := fake.NewFakeClient(
+		labeledConfigMap("frontend-cm", "frontend"),
+		labeledConfigMap("backend-cm", "backend"),
+		labeledConfigMap("database-cm", "database"),
+	)
+
+	systemSection := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(systemSectionYaml), &systemSection)
+	require.NoError(t, err)
+
+	identifiers, err := parseResources(systemSection[resourcesKey].([]interface{}))
+	require.NoError(t, err)
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = identifiers
+
+	err = system.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(system.Identifiers()))
+
+	for _, identifier := range system.Identifiers() {
+		metadata, ok := identifier.(loki.Metadata)
+		require.Equal(t, true, ok)
+		require.Contains(t, []string{"frontend", "backend"}, metadata.Tags()["tier"])
+	}
+
+	destroySection := make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(destroySectionYaml), &destroySection)
+	require.NoError(t, err)
+
+	scenario, err := Destroyer().ParseDestroySection(destroySection)
+	require.NoError(t, err)
+
+	resolved, err := system.Resolve(ctx, scenario)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resolved))
+	require.Equal(t, "frontend-cm", resolved[0].(*ResourceIdentifier).Name)
+}
+
+func TestParseResourcesInvalidSelector(t *testing.T) {
+	_, err := parseResources([]interface{}{
+		map[string]interface{}{
+			apiVersionKey:    "v1",
+			kindKey:          "Pod",
+			labelSelectorKey: "tier in frontend",
+		},
+	})
+	require.Error(t, err)
+
+	_, err = parseResources([]interface{}{
+		map[string]interface{}{
+			apiVersionKey:    "v1",
+			kindKey:          "Pod",
+			nameKey:          "pod1",
+			fieldSelectorKey: "status.phase=Running",
+		},
+	})
+	require.Error(t, err)
+}
+
+func labeledConfigMap(name, tier string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				"tier": tier,
+			},
+		},
+	}
+}
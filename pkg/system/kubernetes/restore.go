@@ -0,0 +1,179 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+const (
+	namespaceKind         = "Namespace"
+	serviceKind           = "Service"
+	clusterIPNone         = "None"
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// serverPopulatedFields are removed from the loaded snapshot of an object before it is re-created.
+var serverPopulatedFields = [][]string{
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+	{"metadata", "selfLink"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"status"},
+}
+
+// Restore re-creates the resources of system which are missing, from the snapshot taken by Load. It restores not only
+// the killed resources but also the ones deleted in cascade. Resources managed by a controller are left to be re-created
+// by their controller. Restoration is performed only if 'restoreOnFailure' is enabled for the system.
+func (s *System) Restore(ctx context.Context, _ ...loki.Identifier) (loki.Identifiers, error) {
+	if !s.restoreOnFailure {
+		return nil, nil
+	}
+
+	identifiers := make([]ResourceIdentifier, 0, len(s.state))
+	for identifier := range s.state {
+		if !s.isOwned(identifier) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+
+	// namespaces are restored before the resources which reside in them.
+	sort.Slice(identifiers, func(i, j int) bool {
+		if (identifiers[i].Kind == namespaceKind) != (identifiers[j].Kind == namespaceKind) {
+			return identifiers[i].Kind == namespaceKind
+		}
+
+		return identifiers[i].ID() < identifiers[j].ID()
+	})
+
+	var restored loki.Identifiers
+
+	for _, identifier := range identifiers {
+		identifier := identifier
+
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(identifier.GroupVersionKind)
+
+		err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: identifier.Namespace, Name: identifier.Name}, object)
+		if err == nil {
+			continue
+		}
+
+		if !k8serrors.IsNotFound(err) {
+			return restored, errors.Wrap(err, "failed to get kubernetes resource")
+		}
+
+		if err := s.k8sClient.Create(ctx, restorable(s.state[identifier])); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				continue
+			}
+
+			return restored, errors.Wrapf(err, "failed to restore resource '%s' of kind '%s' in '%s' namespace",
+				identifier.Name, identifier.Kind, identifier.Namespace)
+		}
+
+		s.logger.Infof("restored resource '%s' of kind '%s' in '%s' namespace",
+			identifier.Name, identifier.Kind, identifier.Namespace)
+		restored = append(restored, &identifier)
+	}
+
+	return restored, nil
+}
+
+// restorable returns copy of the snapshot without the fields populated by kubernetes, so that it can be created again.
+func restorable(snapshot *unstructured.Unstructured) *unstructured.Unstructured {
+	object := snapshot.DeepCopy()
+
+	for _, field := range serverPopulatedFields {
+		unstructured.RemoveNestedField(object.Object, field...)
+	}
+
+	if object.GetKind() == serviceKind {
+		removeServiceAllocations(object)
+	}
+
+	return object
+}
+
+// removeServiceAllocations removes the cluster IPs and node ports which kubernetes allocated to a service, as they may
+// be taken by the time service is re-created. Headless services keep their cluster IP, and node ports which are part of
+// the last applied configuration are kept as they were explicitly set.
+func removeServiceAllocations(object *unstructured.Unstructured) {
+	if clusterIP, _, _ := unstructured.NestedString(object.Object, "spec", "clusterIP"); clusterIP != clusterIPNone {
+		unstructured.RemoveNestedField(object.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(object.Object, "spec", "clusterIPs")
+	}
+
+	applied := make(map[string]interface{})
+	if lastApplied, ok := object.GetAnnotations()[lastAppliedAnnotation]; ok {
+		_ = json.Unmarshal([]byte(lastApplied), &applied)
+	}
+
+	if _, ok, _ := unstructured.NestedFieldNoCopy(applied, "spec", "healthCheckNodePort"); !ok {
+		unstructured.RemoveNestedField(object.Object, "spec", "healthCheckNodePort")
+	}
+
+	explicitNodePorts := make(map[string]bool)
+
+	appliedPorts, _, _ := unstructured.NestedSlice(applied, "spec", "ports")
+	for _, port := range appliedPorts {
+		if nodePort, ok := nestedNodePort(port); ok {
+			explicitNodePorts[nodePort] = true
+		}
+	}
+
+	ports, ok, _ := unstructured.NestedSlice(object.Object, "spec", "ports")
+	if !ok {
+		return
+	}
+
+	for _, port := range ports {
+		if nodePort, ok := nestedNodePort(port); ok && !explicitNodePorts[nodePort] {
+			delete(port.(map[string]interface{}), "nodePort")
+		}
+	}
+
+	_ = unstructured.SetNestedSlice(object.Object, ports, "spec", "ports")
+}
+
+// nestedNodePort returns the node port of a service port, formatted so that numbers decoded as int64 and float64
+// compare equal.
+func nestedNodePort(port interface{}) (string, bool) {
+	portFields, ok := port.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	nodePort, ok := portFields["nodePort"]
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprint(nodePort), true
+}
@@ -0,0 +1,146 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cm",
+			Namespace: "test-ns",
+			UID:       "test-cm-uid",
+		},
+		Data: map[string]string{
+			"key": "value",
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-svc",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": `{"spec":{"ports":[{"port":443,"nodePort":30443}]}}`,
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Type:                  v1.ServiceTypeLoadBalancer,
+			ClusterIP:             "10.0.0.10",
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			HealthCheckNodePort:   31000,
+			Ports: []v1.ServicePort{
+				{
+					Name:     "http",
+					Port:     80,
+					NodePort: 30080,
+				},
+				{
+					Name:     "https",
+					Port:     443,
+					NodePort: 30443,
+				},
+			},
+		},
+	}
+	k8sClient := fake.NewFakeClient(configMap, service, replicaSetPod("web-abc-x1"))
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+			Name:             "test-cm",
+		},
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Namespace:        "test-ns",
+			Name:             "web-abc-x1",
+		},
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Service"},
+			Namespace:        "test-ns",
+			Name:             "test-svc",
+		},
+	}
+
+	err := system.Load(ctx)
+	require.NoError(t, err)
+
+	killer := &Killer{
+		System: system,
+	}
+
+	err = killer.Kill(ctx, system.Identifiers()...)
+	require.NoError(t, err)
+
+	restored, err := system.Restore(ctx, system.Identifiers()...)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(restored))
+
+	system.restoreOnFailure = true
+
+	restored, err = system.Restore(ctx, system.Identifiers()...)
+	require.NoError(t, err)
+	require.Equal(t, loki.Identifiers{system.resourceIdentifiers[0], system.resourceIdentifiers[2]}, restored)
+
+	restoredConfigMap := &v1.ConfigMap{}
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "test-ns", Name: "test-cm"}, restoredConfigMap)
+	require.NoError(t, err)
+	require.Equal(t, configMap.Data, restoredConfigMap.Data)
+	require.NotEqual(t, configMap.UID, restoredConfigMap.UID)
+
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "test-ns", Name: "web-abc-x1"}, &v1.Pod{})
+	require.Error(t, err)
+
+	// allocated cluster IP and node ports are left to be allocated again, while explicitly set node port is kept.
+	restoredService := &v1.Service{}
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "test-ns", Name: "test-svc"}, restoredService)
+	require.NoError(t, err)
+	require.Equal(t, "", restoredService.Spec.ClusterIP)
+	require.Equal(t, int32(0), restoredService.Spec.HealthCheckNodePort)
+	require.Equal(t, int32(0), restoredService.Spec.Ports[0].NodePort)
+	require.Equal(t, int32(30443), restoredService.Spec.Ports[1].NodePort)
+
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"spec": map[string]interface{}{
+			"clusterIP":  "10.0.0.11",
+			"clusterIPs": []interface{}{"10.0.0.11", "fd00::11"},
+		},
+	}}
+	_, ok, _ := unstructured.NestedFieldNoCopy(restorable(object).Object, "spec", "clusterIPs")
+	require.Equal(t, false, ok)
+
+	err = unstructured.SetNestedField(object.Object, "None", "spec", "clusterIP")
+	require.NoError(t, err)
+	clusterIP, _, _ := unstructured.NestedString(restorable(object).Object, "spec", "clusterIP")
+	require.Equal(t, "None", clusterIP)
+}
@@ -18,15 +18,18 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/narahari92/loki/pkg/loki"
@@ -34,33 +37,60 @@ import (
 )
 
 const (
-	kubeconfigKey  = "kubeconfig"
-	inclusterKey   = "incluster"
-	resourcesKey   = "resources"
-	apiVersionKey  = "apiVersion"
-	kindKey        = "kind"
-	nameKey        = "name"
-	namespaceKey   = "namespace"
-	strTypeErrMsg  = "'%s' field should be of type string"
-	reqFieldErrMsg = "'%s' field is required for kubernetes resource"
+	kubeconfigKey    = "kubeconfig"
+	inclusterKey     = "incluster"
+	restoreKey       = "restoreOnFailure"
+	resourcesKey     = "resources"
+	apiVersionKey    = "apiVersion"
+	kindKey          = "kind"
+	nameKey          = "name"
+	namespaceKey     = "namespace"
+	labelSelectorKey = "labelSelector"
+	fieldSelectorKey = "fieldSelector"
+	watchWindow      = time.Minute
+	cacheSyncTimeout = 30 * time.Second
+	extrasTimeout    = 30 * time.Second
+	strTypeErrMsg    = "'%s' field should be of type string"
+	reqFieldErrMsg   = "'%s' field is required for kubernetes resource"
 )
 
 // System represents a kubernetes system comprising of resources as defined in input configuration.
 type System struct {
 	kubeconfig          string
 	inCluster           bool
+	restoreOnFailure    bool
 	k8sClient           client.Client
 	resourceIdentifiers []*ResourceIdentifier
+	restConfig          *rest.Config
+	cache               cache.Cache
+	buildCache          func() (cache.Cache, error)
+	cacheCtx            context.Context
+	stopCache           context.CancelFunc
+	pollOnly            bool
+	watched             map[schema.GroupVersionKind]bool
+	changes             chan struct{}
+	killed              map[types.UID]bool
 	state               map[ResourceIdentifier]*unstructured.Unstructured
+	owners              map[ResourceIdentifier]ownerKey
+	replacements        map[ResourceIdentifier]ResourceIdentifier
+	drift               []loki.Drift
+	extrasPending       bool
 	logger              logrus.FieldLogger
 }
 
 // NewSystem instantiates kubernetes System.
 func NewSystem() *System {
-	return &System{
-		state:  make(map[ResourceIdentifier]*unstructured.Unstructured),
-		logger: logrus.New().WithField("system", system),
+	s := &System{
+		state:        make(map[ResourceIdentifier]*unstructured.Unstructured),
+		owners:       make(map[ResourceIdentifier]ownerKey),
+		replacements: make(map[ResourceIdentifier]ResourceIdentifier),
+		changes:      make(chan struct{}, 1),
+		killed:       make(map[types.UID]bool),
+		logger:       logrus.New().WithField("system", system),
 	}
+	s.buildCache = s.newCache
+
+	return s
 }
 
 // Parse parses the configuration of system given in input configuration.
@@ -83,6 +113,15 @@ func (s *System) Parse(systemConfig map[string]interface{}) error {
 		s.inCluster = inClusterSystem
 	}
 
+	if restore, ok := systemConfig[restoreKey]; ok {
+		restoreOnFailure, ok := restore.(bool)
+		if !ok {
+			return errors.Errorf("'%s' field should be of type bool", restoreKey)
+		}
+
+		s.restoreOnFailure = restoreOnFailure
+	}
+
 	if s.kubeconfig == "" && !s.inCluster {
 		return errors.Errorf("either '%s' or '%s' as true must be specified", kubeconfigKey, inclusterKey)
 	}
@@ -114,8 +153,13 @@ func (s *System) Parse(systemConfig map[string]interface{}) error {
 // Load loads all the kubernetes resources defined in system of input configuration and stores it in memory. This will be
 // used in validation during chaos testing.
 func (s *System) Load(ctx context.Context) error {
+	s.state = make(map[ResourceIdentifier]*unstructured.Unstructured)
+	s.owners = make(map[ResourceIdentifier]ownerKey)
+	s.replacements = make(map[ResourceIdentifier]ResourceIdentifier)
+	s.killed = make(map[types.UID]bool)
+
 	for _, resourceIdentifier := range s.resourceIdentifiers {
-		if resourceIdentifier.Name != "" {
+		if !resourceIdentifier.isGroup() {
 			object := &unstructured.Unstructured{}
 			object.SetGroupVersionKind(resourceIdentifier.GroupVersionKind)
 
@@ -127,19 +171,13 @@ func (s *System) Load(ctx context.Context) error {
 				return errors.Wrap(err, "failed to get kubernetes resource")
 			}
 
-			s.state[*resourceIdentifier] = object
+			s.track(*resourceIdentifier, object)
 			continue
 		}
 
-		objects := &unstructured.UnstructuredList{}
-		objects.SetGroupVersionKind(resourceIdentifier.GroupVersionKind)
-
-		if err := s.k8sClient.List(
-			ctx,
-			objects,
-			&client.ListOptions{Namespace: resourceIdentifier.Namespace},
-		); err != nil {
-			return errors.Wrap(err, "failed to list kubernetes resource")
+		objects, err := s.list(ctx, s.k8sClient, resourceIdentifier)
+		if err != nil {
+			return err
 		}
 
 		for _, object := range objects.Items {
@@ -159,53 +197,242 @@ func (s *System) Load(ctx context.Context) error {
 				Name:      object.GetName(),
 			}
 
-			s.state[resIdent] = &object
+			s.track(resIdent, &object)
 		}
 	}
 
-	return nil
+	return s.watch(ctx)
 }
 
 // Validate validates whether the system is in desired state or not by comparing kubernetes resources at current time with
-// that loaded by Load function.
+// that loaded by Load function. Resources managed by a controller which are replaced under a different name, such as pods
+// of a replica set, are compared with their replacement.
+//
+// Resources are read from an informer cache which is kept up to date by watch events. If the system isn't in desired
+// state, Validate waits for changes to the watched resources and re-evaluates until either the desired state is reached
+// or no change restores it before the deadline of ctx, or within the watch window if ctx has no deadline. Without an
+// informer cache, resources are polled one at a time.
 func (s *System) Validate(ctx context.Context) (bool, error) {
-	backoff := wait.MinMaxBackoff{
-		Min: 250 * time.Millisecond,
-		Max: 500 * time.Millisecond,
+	if !s.watching() {
+		return s.evaluate(ctx, &wait.MinMaxBackoff{
+			Min: 250 * time.Millisecond,
+			Max: 500 * time.Millisecond,
+		})
+	}
+
+	windowDuration := watchWindow
+	if deadline, ok := ctx.Deadline(); ok {
+		windowDuration = time.Until(deadline)
+	}
+
+	window := time.NewTimer(windowDuration)
+	defer window.Stop()
+
+	for {
+		// pending change signals are covered by the evaluation which follows.
+		select {
+		case <-s.changes:
+		default:
+		}
+
+		ok, err := s.evaluate(ctx, nil)
+		if err != nil || ok {
+			return ok, err
+		}
+
+		select {
+		case <-s.changes:
+		case <-window.C:
+			return false, nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return false, nil
+			}
+
+			return false, ctx.Err()
+		}
 	}
+}
+
+// evaluate compares every resource in state with the one read from informer cache, or from API server when polling, and
+// records the drift of the ones which don't match. If backoff is not nil, evaluation pauses between resources for the
+// duration of its step.
+func (s *System) evaluate(ctx context.Context, backoff wait.Backoff) (bool, error) {
+	s.drift = nil
+	s.extrasPending = false
+
+	reader := s.reader()
 
-	for identifier, resource := range s.state {
+	claimed := make(map[string]bool)
+	for identifier := range s.state {
+		claimed[s.current(identifier).Name] = true
+	}
+
+	for _, identifier := range s.sortedIdentifiers() {
+		resource := s.state[identifier]
+		current := s.current(identifier)
 		object := &unstructured.Unstructured{}
-		object.SetGroupVersionKind(identifier.GroupVersionKind)
+		object.SetGroupVersionKind(current.GroupVersionKind)
 
-		if err := s.k8sClient.Get(
+		err := reader.Get(
 			ctx,
-			types.NamespacedName{Namespace: identifier.Namespace, Name: identifier.Name},
-			object); err != nil {
+			types.NamespacedName{Namespace: current.Namespace, Name: current.Name},
+			object)
+
+		switch {
+		case err == nil:
+		case k8serrors.IsNotFound(err) && s.isOwned(identifier):
+			replacement, err := s.findReplacement(ctx, reader, identifier, resource, claimed)
+			if err != nil {
+				return false, err
+			}
+
+			if replacement == nil {
+				s.addDrift(identifier, loki.MissingDrift, "resource is not yet replaced by its controller")
+				continue
+			}
+
+			s.replacements[identifier] = ResourceIdentifier{
+				GroupVersionKind: identifier.GroupVersionKind,
+				Namespace:        replacement.GetNamespace(),
+				Name:             replacement.GetName(),
+			}
+			claimed[replacement.GetName()] = true
+			object = replacement
+		case k8serrors.IsNotFound(err):
+			s.addDrift(identifier, loki.MissingDrift, "resource doesn't exist")
+			continue
+		default:
 			return false, errors.Wrap(err, "failed to get kubernetes resource")
 		}
 
-		ok := isEqual(resource, object)
-		if !ok {
-			s.logger.Warnf("resource '%s' of kind '%s' in '%s' namespace didn't reach desired state",
-				identifier.Name, identifier.Kind, identifier.Namespace)
+		if s.killed[object.GetUID()] {
+			s.addDrift(identifier, loki.MissingDrift, "killed resource is yet to be deleted")
+			continue
+		}
+
+		if drift := driftOf(identifier.ID(), resource, object); len(drift) != 0 {
 			s.logger.Debugf("resource %#v\ndidn't match with\n%#v", resource, object)
-			return ok, nil
+
+			for _, d := range drift {
+				s.addDrift(identifier, d.Type, d.Message)
+			}
+		}
+
+		if backoff != nil {
+			if err := wait.Sleep(ctx, backoff.Step()); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if len(s.drift) == 0 {
+		return true, nil
+	}
+
+	// resources which aren't part of the loaded state are looked up only if drift gets reported, since listing them on
+	// every evaluation would load API server while system recovers.
+	s.extrasPending = true
+
+	return false, nil
+}
+
+// extras records the resources matching group identifiers of the system which are neither part of the loaded state nor
+// replacements of it.
+func (s *System) extras(ctx context.Context) error {
+	known := make(map[ResourceIdentifier]bool)
+	for identifier := range s.state {
+		known[identifier] = true
+		known[s.current(identifier)] = true
+	}
+
+	for _, resourceIdentifier := range s.resourceIdentifiers {
+		if !resourceIdentifier.isGroup() {
+			continue
+		}
+
+		reader := s.reader()
+		if resourceIdentifier.FieldSelector != "" {
+			// informer cache can't filter by field selectors without indexes.
+			reader = s.k8sClient
+		}
+
+		objects, err := s.list(ctx, reader, resourceIdentifier)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range objects.Items {
+			identifier := ResourceIdentifier{
+				GroupVersionKind: resourceIdentifier.GroupVersionKind,
+				Namespace:        object.GetNamespace(),
+				Name:             object.GetName(),
+			}
+
+			if known[identifier] {
+				continue
+			}
+
+			known[identifier] = true
+			s.addDrift(identifier, loki.ExtraDrift, "resource isn't part of the loaded state")
 		}
+	}
+
+	return nil
+}
+
+func (s *System) addDrift(identifier ResourceIdentifier, driftType loki.DriftType, message string) {
+	s.logger.Debugf("resource '%s' of kind '%s' in '%s' namespace drifted from desired state (%s): %s",
+		identifier.Name, identifier.Kind, identifier.Namespace, driftType, message)
+
+	s.drift = append(s.drift, loki.Drift{
+		ID:      identifier.ID(),
+		Type:    driftType,
+		Message: message,
+	})
+}
+
+// Drift returns the deviations from desired state found by the last call to Validate. Resources which aren't part of the
+// loaded state are looked up on the first call after a failed validation.
+func (s *System) Drift() []loki.Drift {
+	if s.extrasPending {
+		s.extrasPending = false
+
+		ctx, cancel := context.WithTimeout(context.Background(), extrasTimeout)
+		defer cancel()
+
+		if err := s.extras(ctx); err != nil {
+			s.logger.WithError(err).Warn("failed to look up resources which aren't part of the loaded state")
+		}
+	}
+
+	return s.drift
+}
 
-		time.Sleep(backoff.Step())
+func (s *System) sortedIdentifiers() []ResourceIdentifier {
+	identifiers := make([]ResourceIdentifier, 0, len(s.state))
+	for identifier := range s.state {
+		identifiers = append(identifiers, identifier)
 	}
 
-	return true, nil
+	sort.Slice(identifiers, func(i, j int) bool {
+		return identifiers[i].ID() < identifiers[j].ID()
+	})
+
+	return identifiers
 }
 
-// Identifiers return Identifier values of all resources in the kubernetes system.
+// Identifiers return Identifier values of all resources in the kubernetes system. Labels of resources are available as
+// tags of their identifiers.
 func (s *System) Identifiers() loki.Identifiers {
 	var identifiers loki.Identifiers
 
-	for identifier := range s.state {
+	for identifier, object := range s.state {
 		identifier := identifier
-		identifiers = append(identifiers, &identifier)
+		identifiers = append(identifiers, &loadedResource{
+			ResourceIdentifier: &identifier,
+			labels:             object.GetLabels(),
+		})
 	}
 
 	return identifiers
@@ -229,6 +456,105 @@ func (s *System) AsJSON(ctx context.Context, reload bool) ([]byte, error) {
 	return json.Marshal(objects)
 }
 
+// Resolve resolves the identifiers which represent groups of resources, such as the ones with label or field selectors,
+// into individual resources of the system. Resources which are not part of the loaded state are left out.
+func (s *System) Resolve(ctx context.Context, identifiers loki.Identifiers) (loki.Identifiers, error) {
+	var resolved loki.Identifiers
+
+	included := make(map[ResourceIdentifier]bool)
+
+	for _, identifier := range identifiers {
+		resourceIdentifier, ok := asResourceIdentifier(identifier)
+		if !ok {
+			return nil, errors.New("unsupported identifier passed to kubernetes system")
+		}
+
+		if !resourceIdentifier.isGroup() {
+			if !included[*resourceIdentifier] {
+				included[*resourceIdentifier] = true
+				resolved = append(resolved, resourceIdentifier)
+			}
+
+			continue
+		}
+
+		objects, err := s.list(ctx, s.k8sClient, resourceIdentifier)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects.Items {
+			resIdent := ResourceIdentifier{
+				GroupVersionKind: resourceIdentifier.GroupVersionKind,
+				Namespace:        object.GetNamespace(),
+				Name:             object.GetName(),
+			}
+
+			if _, ok := s.state[resIdent]; !ok || included[resIdent] {
+				continue
+			}
+
+			included[resIdent] = true
+			resolved = append(resolved, &resIdent)
+		}
+	}
+
+	return resolved, nil
+}
+
+func (s *System) list(
+	ctx context.Context,
+	reader client.Reader,
+	resourceIdentifier *ResourceIdentifier,
+) (*unstructured.UnstructuredList, error) {
+	listOptions, err := resourceIdentifier.listOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := newList(resourceIdentifier.GroupVersionKind)
+
+	if err := reader.List(ctx, objects, listOptions); err != nil {
+		return nil, errors.Wrap(err, "failed to list kubernetes resource")
+	}
+
+	return objects, nil
+}
+
+func newList(gvk schema.GroupVersionKind) *unstructured.UnstructuredList {
+	objects := &unstructured.UnstructuredList{}
+	objects.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind + "List",
+	})
+
+	return objects
+}
+
+func (s *System) track(identifier ResourceIdentifier, object *unstructured.Unstructured) {
+	s.state[identifier] = object
+
+	if key, ok := ownerKeyOf(object); ok {
+		s.owners[identifier] = key
+	}
+}
+
+func (s *System) isOwned(identifier ResourceIdentifier) bool {
+	_, ok := s.owners[identifier]
+	return ok
+}
+
+// current returns the identifier of the object which currently stands for the resource loaded into state. It differs
+// from the loaded identifier only when the object got replaced by its controller.
+func (s *System) current(identifier ResourceIdentifier) ResourceIdentifier {
+	if replacement, ok := s.replacements[identifier]; ok {
+		return replacement
+	}
+
+	return identifier
+}
+
 func (s *System) createClient() error {
 	var restCfg *rest.Config
 
@@ -258,6 +584,7 @@ func (s *System) createClient() error {
 		return errors.Wrap(err, "failed to create kubernetes client")
 	}
 
+	s.restConfig = restCfg
 	s.k8sClient = k8sClient
 
 	return nil
@@ -0,0 +1,190 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// watch makes sure informers are running for all kinds of resources in the state of system, so that validation can be
+// evaluated against the informer cache and triggered by watch events. Informers keep running until the context passed
+// is done, after which a subsequent call starts them afresh. The informer cache is scoped to the namespaces of the
+// resources of system, so that namespace scoped permissions suffice. If informers fail to sync within the sync timeout,
+// for instance due to missing permissions, the informer cache is stopped and validation falls back to polling.
+func (s *System) watch(ctx context.Context) error {
+	if s.restConfig == nil || s.pollOnly {
+		return nil
+	}
+
+	if s.cache == nil || s.cacheCtx.Err() != nil {
+		informerCache, err := s.buildCache()
+		if err != nil {
+			return errors.Wrap(err, "failed to create informer cache")
+		}
+
+		cacheCtx, stop := context.WithCancel(ctx)
+
+		go func() {
+			if err := informerCache.Start(cacheCtx.Done()); err != nil {
+				s.logger.WithError(err).Error("informer cache stopped")
+			}
+		}()
+
+		s.cache = informerCache
+		s.cacheCtx = cacheCtx
+		s.stopCache = stop
+		s.watched = make(map[schema.GroupVersionKind]bool)
+	}
+
+	syncCtx, cancel := context.WithTimeout(s.cacheCtx, cacheSyncTimeout)
+	defer cancel()
+
+	for _, gvk := range s.watchedKinds() {
+		if s.watched[gvk] {
+			continue
+		}
+
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(gvk)
+
+		informer, err := s.cache.GetInformer(syncCtx, object)
+		if err != nil {
+			return s.poll(ctx, errors.Wrapf(err, "failed to get informer for kind '%s'", gvk.Kind))
+		}
+
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { s.notify() },
+			UpdateFunc: func(interface{}, interface{}) { s.notify() },
+			DeleteFunc: func(interface{}) { s.notify() },
+		})
+
+		s.watched[gvk] = true
+	}
+
+	if !s.cache.WaitForCacheSync(syncCtx.Done()) {
+		return s.poll(ctx, errors.Errorf("failed to sync informer cache within %s", cacheSyncTimeout))
+	}
+
+	return nil
+}
+
+// watchedKinds returns the kinds of resources in the state of system as well as the kinds of its group identifiers, as
+// resources which aren't part of the state are looked up among the latter.
+func (s *System) watchedKinds() []schema.GroupVersionKind {
+	var kinds []schema.GroupVersionKind
+
+	seen := make(map[schema.GroupVersionKind]bool)
+
+	add := func(gvk schema.GroupVersionKind) {
+		if !seen[gvk] {
+			seen[gvk] = true
+			kinds = append(kinds, gvk)
+		}
+	}
+
+	for identifier := range s.state {
+		add(identifier.GroupVersionKind)
+	}
+
+	for _, resourceIdentifier := range s.resourceIdentifiers {
+		if resourceIdentifier.isGroup() {
+			add(resourceIdentifier.GroupVersionKind)
+		}
+	}
+
+	return kinds
+}
+
+// newCache creates an informer cache scoped to the namespaces of the resources of system. Cache is cluster wide if any
+// of the resources isn't namespaced.
+func (s *System) newCache() (cache.Cache, error) {
+	namespaces := s.cacheNamespaces()
+
+	switch len(namespaces) {
+	case 0:
+		return cache.New(s.restConfig, cache.Options{})
+	case 1:
+		return cache.New(s.restConfig, cache.Options{Namespace: namespaces[0]})
+	default:
+		return cache.MultiNamespacedCacheBuilder(namespaces)(s.restConfig, cache.Options{})
+	}
+}
+
+// cacheNamespaces returns the namespaces to which informer cache is scoped, or nil if it has to be cluster wide.
+func (s *System) cacheNamespaces() []string {
+	var namespaces []string
+
+	seen := make(map[string]bool)
+
+	for _, resourceIdentifier := range s.resourceIdentifiers {
+		if resourceIdentifier.Namespace == "" {
+			return nil
+		}
+
+		if !seen[resourceIdentifier.Namespace] {
+			seen[resourceIdentifier.Namespace] = true
+			namespaces = append(namespaces, resourceIdentifier.Namespace)
+		}
+	}
+
+	return namespaces
+}
+
+// poll stops the informer cache which failed with err, so that validation polls the resources instead. Informers
+// aren't started again by subsequent loads. err is returned as is if ctx is done, as then the failure isn't due to
+// informers.
+func (s *System) poll(ctx context.Context, err error) error {
+	s.stopCache()
+	s.cache = nil
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	s.logger.WithError(err).Warn("falling back to polling for validation")
+	s.pollOnly = true
+
+	return nil
+}
+
+// notify signals that a watched resource changed. Signals are coalesced as validation re-evaluates the whole state.
+func (s *System) notify() {
+	select {
+	case s.changes <- struct{}{}:
+	default:
+	}
+}
+
+// watching returns whether validation can be evaluated against a running informer cache.
+func (s *System) watching() bool {
+	return s.cache != nil && s.cacheCtx.Err() == nil
+}
+
+// reader returns the reader against which desired state is evaluated, which is the informer cache when it's running and
+// API server otherwise.
+func (s *System) reader() client.Reader {
+	if s.watching() {
+		return s.cache
+	}
+
+	return s.k8sClient
+}
@@ -0,0 +1,451 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/narahari92/loki/pkg/loki"
+)
+
+func TestWatchEventTriggersValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient, system := watchedSystem(ctx, t, "watch-event-ns")
+
+	configMap := &v1.ConfigMap{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "watch-event-ns", Name: "watched-cm"}, configMap)
+	require.NoError(t, err)
+
+	configMap.Data["key"] = "drifted"
+	err = k8sClient.Update(ctx, configMap)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		ok, err := system.evaluate(ctx, nil)
+		return err == nil && !ok
+	}, 10*time.Second, 100*time.Millisecond)
+
+	validateCtx, cancelValidate := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelValidate()
+
+	type validation struct {
+		ok  bool
+		err error
+	}
+
+	result := make(chan validation, 1)
+
+	go func() {
+		ok, err := system.Validate(validateCtx)
+		result <- validation{ok: ok, err: err}
+	}()
+
+	configMap.Data["key"] = "value"
+	err = k8sClient.Update(ctx, configMap)
+	require.NoError(t, err)
+
+	select {
+	case validated := <-result:
+		require.NoError(t, validated.err)
+		require.Equal(t, true, validated.ok)
+	case <-time.After(20 * time.Second):
+		require.Fail(t, "validation wasn't re-evaluated on watch event")
+	}
+}
+
+func TestWatchKilledResourceInStaleCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient, system := watchedSystem(ctx, t, "watch-killed-ns")
+
+	configMap := &v1.ConfigMap{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "watch-killed-ns", Name: "watched-cm"}, configMap)
+	require.NoError(t, err)
+
+	// cache still holds the object with killed UID, as if its deletion isn't observed yet.
+	system.killed[configMap.UID] = true
+
+	ok, err := system.evaluate(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+	require.Equal(t, []loki.Drift{
+		{
+			ID:      system.resourceIdentifiers[0].ID(),
+			Type:    loki.MissingDrift,
+			Message: "killed resource is yet to be deleted",
+		},
+	}, system.Drift())
+
+	err = k8sClient.Delete(ctx, configMap)
+	require.NoError(t, err)
+
+	err = k8sClient.Create(ctx, watchedConfigMap("watch-killed-ns"))
+	require.NoError(t, err)
+
+	validateCtx, cancelValidate := context.WithTimeout(ctx, 20*time.Second)
+	defer cancelValidate()
+
+	ok, err = system.Validate(validateCtx)
+	require.NoError(t, err)
+	require.Equal(t, true, ok)
+}
+
+func TestWatchWindowExpires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient, system := watchedSystem(ctx, t, "watch-window-ns")
+
+	configMap := &v1.ConfigMap{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "watch-window-ns", Name: "watched-cm"}, configMap)
+	require.NoError(t, err)
+
+	err = k8sClient.Delete(ctx, configMap)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		ok, err := system.evaluate(ctx, nil)
+		return err == nil && !ok
+	}, 10*time.Second, 100*time.Millisecond)
+
+	validateCtx, cancelValidate := context.WithTimeout(ctx, 2*time.Second)
+	defer cancelValidate()
+
+	ok, err := system.Validate(validateCtx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+	require.Error(t, validateCtx.Err())
+
+	canceledCtx, cancelCanceled := context.WithCancel(ctx)
+	cancelCanceled()
+
+	_, err = system.Validate(canceledCtx)
+	require.Error(t, err)
+}
+
+func TestWatchOrPoll(t *testing.T) {
+	tests := []struct {
+		description   string
+		restConfig    *rest.Config
+		informerErr   error
+		unsynced      bool
+		expectedWatch bool
+		expectedPoll  bool
+	}{
+		{
+			description: "no rest config",
+		},
+		{
+			description:   "informers sync",
+			restConfig:    &rest.Config{},
+			expectedWatch: true,
+		},
+		{
+			description:  "informer fails",
+			restConfig:   &rest.Config{},
+			informerErr:  errors.New("forbidden"),
+			expectedPoll: true,
+		},
+		{
+			description:  "sync times out",
+			restConfig:   &rest.Config{},
+			unsynced:     true,
+			expectedPoll: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			k8sClient := &countingClient{Client: fake.NewFakeClient(labeledConfigMap("cm-a", "front"))}
+			informerCache := &fakeCache{
+				Reader:      fake.NewFakeClient(labeledConfigMap("cm-a", "front")),
+				informerErr: test.informerErr,
+				unsynced:    test.unsynced,
+			}
+
+			var builds int
+
+			system := cachedSystem(k8sClient, informerCache, &builds)
+			system.restConfig = test.restConfig
+
+			err := system.Load(ctx)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedWatch, system.watching())
+			require.Equal(t, test.expectedPoll, system.pollOnly)
+
+			ok, err := system.Validate(ctx)
+			require.NoError(t, err)
+			require.Equal(t, true, ok)
+
+			if test.expectedWatch {
+				require.Equal(t, int32(1), atomic.LoadInt32(&informerCache.gets))
+			} else {
+				require.Equal(t, int32(0), atomic.LoadInt32(&informerCache.gets))
+			}
+
+			// informers aren't started again once validation fell back to polling.
+			err = system.Load(ctx)
+			require.NoError(t, err)
+
+			if test.restConfig == nil {
+				require.Equal(t, 0, builds)
+			} else {
+				require.Equal(t, 1, builds)
+			}
+		})
+	}
+}
+
+func TestWatchReadsFromCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient := &countingClient{Client: fake.NewFakeClient(labeledConfigMap("cm-a", "front"))}
+	informerCache := &fakeCache{
+		Reader: fake.NewFakeClient(labeledConfigMap("cm-a", "back"), labeledConfigMap("cm-b", "front")),
+	}
+
+	var builds int
+
+	system := cachedSystem(k8sClient, informerCache, &builds)
+	system.restConfig = &rest.Config{}
+
+	err := system.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, true, system.watching())
+
+	listed := atomic.LoadInt32(&k8sClient.lists)
+
+	validateCtx, cancelValidate := context.WithTimeout(ctx, time.Second)
+	defer cancelValidate()
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			system.notify()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+
+	ok, err := system.Validate(validateCtx)
+	require.NoError(t, err)
+	require.Equal(t, false, ok)
+	require.Greater(t, atomic.LoadInt32(&informerCache.gets), int32(1))
+
+	// resources are neither listed on every re-evaluation nor from API server.
+	require.Equal(t, int32(0), atomic.LoadInt32(&informerCache.lists))
+	require.Equal(t, listed, atomic.LoadInt32(&k8sClient.lists))
+
+	expected := []loki.Drift{
+		{
+			ID:      "loki:kubernetes-resource:/v1, Kind=ConfigMap, test-ns/cm-a",
+			Type:    loki.LabelDrift,
+			Message: "label 'tier' is 'back', expected 'front'",
+		},
+		{
+			ID:      "loki:kubernetes-resource:/v1, Kind=ConfigMap, test-ns/cm-b",
+			Type:    loki.ExtraDrift,
+			Message: "resource isn't part of the loaded state",
+		},
+	}
+	require.Equal(t, expected, system.Drift())
+	require.Equal(t, expected, system.Drift())
+	require.Equal(t, int32(1), atomic.LoadInt32(&informerCache.lists))
+	require.Equal(t, listed, atomic.LoadInt32(&k8sClient.lists))
+}
+
+func TestCacheNamespaces(t *testing.T) {
+	configMaps := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	namespaces := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+	tests := []struct {
+		description string
+		identifiers []*ResourceIdentifier
+		expected    []string
+	}{
+		{
+			description: "single namespace",
+			identifiers: []*ResourceIdentifier{
+				{GroupVersionKind: configMaps, Namespace: "ns-a", Name: "cm-a"},
+				{GroupVersionKind: configMaps, Namespace: "ns-a"},
+			},
+			expected: []string{"ns-a"},
+		},
+		{
+			description: "multiple namespaces",
+			identifiers: []*ResourceIdentifier{
+				{GroupVersionKind: configMaps, Namespace: "ns-a"},
+				{GroupVersionKind: configMaps, Namespace: "ns-b"},
+				{GroupVersionKind: configMaps, Namespace: "ns-a", Name: "cm-a"},
+			},
+			expected: []string{"ns-a", "ns-b"},
+		},
+		{
+			description: "cluster scoped resource",
+			identifiers: []*ResourceIdentifier{
+				{GroupVersionKind: configMaps, Namespace: "ns-a"},
+				{GroupVersionKind: namespaces, Name: "ns-a"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			system := NewSystem()
+			system.resourceIdentifiers = test.identifiers
+
+			require.Equal(t, test.expected, system.cacheNamespaces())
+		})
+	}
+}
+
+// cachedSystem returns system watching config maps of test-ns with informerCache, counting the caches built in builds.
+func cachedSystem(k8sClient client.Client, informerCache cache.Cache, builds *int) *System {
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        "test-ns",
+			LabelSelector:    "tier",
+		},
+	}
+	system.buildCache = func() (cache.Cache, error) {
+		*builds++
+		return informerCache, nil
+	}
+
+	return system
+}
+
+// countingClient counts the lists made by client.
+type countingClient struct {
+	client.Client
+	lists int32
+}
+
+func (c *countingClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	atomic.AddInt32(&c.lists, 1)
+	return c.Client.List(ctx, list, opts...)
+}
+
+// fakeCache is an informer cache which serves reads from Reader and counts them. Its informers fail with informerErr if
+// set, and don't sync if unsynced is set.
+type fakeCache struct {
+	client.Reader
+	cache.Informers
+	informerErr error
+	unsynced    bool
+	gets        int32
+	lists       int32
+}
+
+func (f *fakeCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	atomic.AddInt32(&f.gets, 1)
+	return f.Reader.Get(ctx, key, obj)
+}
+
+func (f *fakeCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	atomic.AddInt32(&f.lists, 1)
+	return f.Reader.List(ctx, list, opts...)
+}
+
+func (f *fakeCache) GetInformer(context.Context, runtime.Object) (cache.Informer, error) {
+	if f.informerErr != nil {
+		return nil, f.informerErr
+	}
+
+	return &fakeInformer{}, nil
+}
+
+func (f *fakeCache) Start(stop <-chan struct{}) error {
+	<-stop
+	return nil
+}
+
+func (f *fakeCache) WaitForCacheSync(<-chan struct{}) bool {
+	return !f.unsynced
+}
+
+type fakeInformer struct {
+	cache.Informer
+}
+
+func (f *fakeInformer) AddEventHandler(toolscache.ResourceEventHandler) {}
+
+// watchedSystem creates a config map in namespace and returns system loaded with it, which validates against informer
+// cache running until ctx is done.
+func watchedSystem(ctx context.Context, t *testing.T, namespace string) (client.Client, *System) {
+	k8sClient, err := client.New(cfg, client.Options{})
+	require.NoError(t, err)
+
+	err = k8sClient.Create(ctx, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	require.NoError(t, err)
+
+	err = k8sClient.Create(ctx, watchedConfigMap(namespace))
+	require.NoError(t, err)
+
+	system := NewSystem()
+	system.k8sClient = k8sClient
+	system.restConfig = cfg
+	system.resourceIdentifiers = []*ResourceIdentifier{
+		{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace:        namespace,
+			Name:             "watched-cm",
+		},
+	}
+
+	err = system.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, true, system.watching())
+
+	return k8sClient, system
+}
+
+func watchedConfigMap(namespace string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "watched-cm",
+		},
+		Data: map[string]string{
+			"key": "value",
+		},
+	}
+}
@@ -25,7 +25,8 @@ type Backoff interface {
 	Step() time.Duration
 }
 
-// ExecuteWithBackoff executes given function with backoff in case of failure.
+// ExecuteWithBackoff executes given function with backoff in case of failure. It stops retrying and returns the error of
+// context once the context is done.
 func ExecuteWithBackoff(ctx context.Context, backoff Backoff, run func(ctx context.Context) (bool, error), timeout time.Duration) (bool, error) {
 	start := time.Now()
 
@@ -40,8 +41,22 @@ func ExecuteWithBackoff(ctx context.Context, backoff Backoff, run func(ctx conte
 			return ok, err
 		}
 
-		sleepDuration := backoff.Step()
+		if err := Sleep(ctx, backoff.Step()); err != nil {
+			return false, err
+		}
+	}
+}
 
-		time.Sleep(sleepDuration)
+// Sleep pauses for given duration or until the context is done, whichever happens first. It returns the error of context
+// if the context is done.
+func Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
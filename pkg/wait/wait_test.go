@@ -90,3 +90,22 @@ func TestExecuteWithBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteWithBackoffCancellation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	before := time.Now()
+	ok, err := ExecuteWithBackoff(
+		ctx,
+		&MinMaxBackoff{Min: 10 * time.Second, Max: 20 * time.Second},
+		func(ctx context.Context) (bool, error) {
+			return false, nil
+		},
+		time.Minute,
+	)
+
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, false, ok)
+	require.Less(t, int64(time.Since(before)), int64(time.Second))
+}